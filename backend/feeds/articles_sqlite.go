@@ -0,0 +1,269 @@
+package feeds
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Article is a single ingested feed item as stored in the articles table.
+// Titles and descriptions are kept in the feed's original language.
+type Article struct {
+	ID          string
+	FeedURL     string
+	GUID        string
+	Link        string
+	Title       string
+	Description string
+	Published   string     // raw published string as found in the feed
+	PublishedAt *time.Time // parsed publish time, nil if the feed had none
+	Updated     string     // raw updated string as found in the feed
+	UpdatedAt   *time.Time // parsed update time, nil if the feed had none
+	Source      string
+	Image       string
+	Authors     []string
+	Categories  []string
+	Enclosures  []Enclosure
+	Lead        string // first paragraph extracted from the article page, if enabled
+	ReadingMins int    // estimated reading time of the extracted page
+	Language    string // ISO 639-1 code, declared by the feed or detected from the text
+}
+
+// Enclosure is a media file attached to a feed item.
+type Enclosure struct {
+	URL    string `json:"url"`
+	Type   string `json:"type,omitempty"`
+	Length string `json:"length,omitempty"`
+}
+
+const articleColumns = `id, feed_url, guid, link, title, description, published, published_at,
+	item_updated, item_updated_at, source, image, authors, categories, enclosures, lead, reading_minutes, language`
+
+// ArticleID derives a stable identifier for a feed item. It prefers the item's GUID,
+// then its link, then its title, so the same item always maps to the same ID.
+func ArticleID(feedURL, guid, link, title string) string {
+	key := guid
+	if key == "" {
+		key = link
+	}
+	if key == "" {
+		key = title
+	}
+	sum := sha256.Sum256([]byte(feedURL + "\x00" + key))
+	return hex.EncodeToString(sum[:16])
+}
+
+// UpsertArticles inserts new articles and refreshes existing ones in a single transaction.
+func UpsertArticles(articles []Article) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO articles (` + articleColumns + `, first_seen_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			link = excluded.link,
+			title = excluded.title,
+			description = excluded.description,
+			published = excluded.published,
+			published_at = excluded.published_at,
+			item_updated = excluded.item_updated,
+			item_updated_at = excluded.item_updated_at,
+			source = excluded.source,
+			image = excluded.image,
+			authors = excluded.authors,
+			categories = excluded.categories,
+			enclosures = excluded.enclosures,
+			language = excluded.language,
+			updated_at = excluded.updated_at
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare article upsert: %w", err)
+	}
+	defer stmt.Close()
+
+	now := time.Now().Unix()
+	for _, a := range articles {
+		if a.ID == "" {
+			a.ID = ArticleID(a.FeedURL, a.GUID, a.Link, a.Title)
+		}
+		if _, err := stmt.Exec(
+			a.ID, a.FeedURL, a.GUID, a.Link, a.Title, a.Description,
+			a.Published, unixOrNil(a.PublishedAt), a.Updated, unixOrNil(a.UpdatedAt), a.Source,
+			a.Image, jsonList(a.Authors), jsonList(a.Categories), jsonList(a.Enclosures),
+			a.Lead, a.ReadingMins, a.Language, now, now,
+		); err != nil {
+			return fmt.Errorf("failed to save article %s: %w", a.ID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit articles: %w", err)
+	}
+	return nil
+}
+
+// PruneArticles deletes articles published before the given time that no feed has
+// listed since then, along with their search index entries. It returns the number of
+// deleted articles.
+func PruneArticles(before time.Time) (int64, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	const stale = `updated_at < ? AND COALESCE(published_at, first_seen_at) < ?`
+	if _, err := tx.Exec(`
+		DELETE FROM articles_fts WHERE article_id IN (SELECT id FROM articles WHERE `+stale+`)
+	`, before.Unix(), before.Unix()); err != nil {
+		return 0, fmt.Errorf("failed to unindex old articles: %w", err)
+	}
+	res, err := tx.Exec(`DELETE FROM articles WHERE `+stale, before.Unix(), before.Unix())
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune articles: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to prune articles: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+// GetArticlesByFeed returns the most recent stored articles for a feed URL, newest first.
+func GetArticlesByFeed(feedURL string, limit int) ([]Article, error) {
+	rows, err := db.Query(`
+		SELECT `+articleColumns+`
+		FROM articles
+		WHERE feed_url = ?
+		ORDER BY COALESCE(published_at, first_seen_at) DESC, id ASC
+		LIMIT ?
+	`, feedURL, limit)
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	defer rows.Close()
+
+	return scanArticles(rows)
+}
+
+// GetArticlesByFeedSince returns the stored articles of a feed URL published (or first
+// seen) since the given time.
+func GetArticlesByFeedSince(feedURL string, since time.Time) ([]Article, error) {
+	rows, err := db.Query(`
+		SELECT `+articleColumns+`
+		FROM articles
+		WHERE feed_url = ? AND COALESCE(published_at, first_seen_at) >= ?
+	`, feedURL, since.Unix())
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	defer rows.Close()
+
+	return scanArticles(rows)
+}
+
+// ExtractedArticleIDs returns the IDs of a feed's articles whose page content
+// has already been extracted, whether or not extraction found anything.
+func ExtractedArticleIDs(feedURL string) (map[string]bool, error) {
+	rows, err := db.Query(`SELECT id FROM articles WHERE feed_url = ? AND extracted_at IS NOT NULL`, feedURL)
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	defer rows.Close()
+
+	ids := make(map[string]bool)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan article id: %w", err)
+		}
+		ids[id] = true
+	}
+	return ids, rows.Err()
+}
+
+// SetArticleExtraction stores the result of extracting an article's page content.
+func SetArticleExtraction(id, lead string, readingMins int) error {
+	_, err := db.Exec(`
+		UPDATE articles SET lead = ?, reading_minutes = ?, extracted_at = ?
+		WHERE id = ?
+	`, lead, readingMins, time.Now().Unix(), id)
+	if err != nil {
+		return fmt.Errorf("failed to save extraction for %s: %w", id, err)
+	}
+	return nil
+}
+
+// GetArticle returns a single stored article by ID.
+func GetArticle(id string) (*Article, error) {
+	rows, err := db.Query(`SELECT `+articleColumns+` FROM articles WHERE id = ?`, id)
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	defer rows.Close()
+
+	articles, err := scanArticles(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(articles) == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return &articles[0], nil
+}
+
+// scanArticles reads all rows of an articles query selecting articleColumns.
+func scanArticles(rows *sql.Rows) ([]Article, error) {
+	var result []Article
+	for rows.Next() {
+		var (
+			a                          Article
+			publishedAt, updatedAt     sql.NullInt64
+			authors, categories, encls string
+		)
+		if err := rows.Scan(
+			&a.ID, &a.FeedURL, &a.GUID, &a.Link, &a.Title, &a.Description,
+			&a.Published, &publishedAt, &a.Updated, &updatedAt, &a.Source,
+			&a.Image, &authors, &categories, &encls, &a.Lead, &a.ReadingMins, &a.Language,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan article: %w", err)
+		}
+		a.PublishedAt = timeOrNil(publishedAt)
+		a.UpdatedAt = timeOrNil(updatedAt)
+
+		// Malformed lists are treated as empty rather than failing the whole query
+		_ = json.Unmarshal([]byte(authors), &a.Authors)
+		_ = json.Unmarshal([]byte(categories), &a.Categories)
+		_ = json.Unmarshal([]byte(encls), &a.Enclosures)
+
+		result = append(result, a)
+	}
+	return result, rows.Err()
+}
+
+// unixOrNil converts an optional time into a nullable SQLite integer.
+func unixOrNil(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return t.Unix()
+}
+
+// jsonList encodes a slice for a JSON list column, storing nil as an empty list.
+func jsonList[T any](values []T) string {
+	if len(values) == 0 {
+		return "[]"
+	}
+	data, err := json.Marshal(values)
+	if err != nil {
+		return "[]"
+	}
+	return string(data)
+}
@@ -0,0 +1,73 @@
+package feeds
+
+import (
+	"testing"
+	"time"
+)
+
+// openTestDB replaces the package database with a private in-memory one.
+func openTestDB(t *testing.T) {
+	t.Helper()
+	if err := Open("file:" + t.Name() + "?mode=memory&cache=shared&_pragma=busy_timeout(5000)"); err != nil {
+		t.Fatalf("opening test database: %v", err)
+	}
+}
+
+func TestArticleID(t *testing.T) {
+	const feed = "https://a.example/rss"
+	id := ArticleID(feed, "guid-1", "https://a.example/1", "Title")
+
+	if ArticleID(feed, "guid-1", "https://a.example/1", "Title") != id {
+		t.Error("the same item got a different ID")
+	}
+	if ArticleID(feed, "guid-1", "https://a.example/changed", "Edited title") != id {
+		t.Error("editing link or title changed the ID of an item with a GUID")
+	}
+	if ArticleID("https://b.example/rss", "guid-1", "https://a.example/1", "Title") == id {
+		t.Error("the same GUID in another feed got the same ID")
+	}
+	if ArticleID(feed, "", "https://a.example/1", "Title") != ArticleID(feed, "", "https://a.example/1", "Other") {
+		t.Error("without a GUID the link should identify the item")
+	}
+	if ArticleID(feed, "", "", "Title") == ArticleID(feed, "", "", "Other") {
+		t.Error("without GUID and link different titles got the same ID")
+	}
+	if len(id) != 32 {
+		t.Errorf("ID %q is not 32 hex characters", id)
+	}
+}
+
+func TestUpsertArticlesUpdatesInPlace(t *testing.T) {
+	openTestDB(t)
+	const feed = "https://a.example/rss"
+	older := time.Date(2025, 5, 1, 8, 0, 0, 0, time.UTC)
+	newer := older.Add(time.Hour)
+
+	if err := UpsertArticles([]Article{
+		{FeedURL: feed, GUID: "1", Title: "Old headline", PublishedAt: &older},
+		{FeedURL: feed, GUID: "2", Title: "Later story", PublishedAt: &newer},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	// The publisher corrects the headline of the first item
+	if err := UpsertArticles([]Article{{FeedURL: feed, GUID: "1", Title: "Corrected headline", PublishedAt: &older}}); err != nil {
+		t.Fatal(err)
+	}
+
+	stored, err := GetArticlesByFeed(feed, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stored) != 2 {
+		t.Fatalf("stored %d articles, want 2", len(stored))
+	}
+	if stored[0].Title != "Later story" || stored[1].Title != "Corrected headline" {
+		t.Errorf("got %q, %q; want the newest first and the corrected headline", stored[0].Title, stored[1].Title)
+	}
+	if stored[1].ID != ArticleID(feed, "1", "", "Old headline") {
+		t.Errorf("the stored ID %s is not derived from the GUID", stored[1].ID)
+	}
+	if stored[1].PublishedAt == nil || !stored[1].PublishedAt.Equal(older) {
+		t.Errorf("published at %v, want %v", stored[1].PublishedAt, older)
+	}
+}
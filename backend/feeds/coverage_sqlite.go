@@ -0,0 +1,58 @@
+package feeds
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// CountryStats summarizes the enabled feeds of a country and their recent output.
+type CountryStats struct {
+	Country        string
+	Feeds          int
+	HealthyFeeds   int        // fetched successfully and not failing since
+	RecentArticles int        // articles published (or first seen) since the requested time
+	LastSuccessAt  *time.Time // most recent successful fetch of any of the feeds
+}
+
+// ListCountryStats returns the feed statistics of every country with enabled feeds,
+// counting the articles published since the given time. Only ISO alpha-2 codes are
+// countries; other channels such as ocean regions are left out. Articles of feeds with
+// keywords are not counted, as SQL cannot apply their filter; see GetArticlesByFeedSince.
+func ListCountryStats(since time.Time) ([]CountryStats, error) {
+	rows, err := db.Query(`
+		SELECT fs.country,
+			COUNT(*),
+			SUM(CASE WHEN st.checked_at IS NOT NULL AND st.consecutive_failures = 0 THEN 1 ELSE 0 END),
+			MAX(st.checked_at),
+			(
+				SELECT COUNT(*)
+				FROM articles a
+				WHERE a.feed_url IN (SELECT url FROM feed_sources WHERE country = fs.country AND enabled = 1 AND keywords = '[]')
+					AND COALESCE(a.published_at, a.first_seen_at) >= ?
+			)
+		FROM feed_sources fs
+		LEFT JOIN feed_state st ON st.url = fs.url
+		WHERE fs.enabled = 1 AND fs.country GLOB '[A-Z][A-Z]'
+		GROUP BY fs.country
+		ORDER BY fs.country
+	`, since.Unix())
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	defer rows.Close()
+
+	var result []CountryStats
+	for rows.Next() {
+		var (
+			s             CountryStats
+			lastSuccessAt sql.NullInt64
+		)
+		if err := rows.Scan(&s.Country, &s.Feeds, &s.HealthyFeeds, &lastSuccessAt, &s.RecentArticles); err != nil {
+			return nil, fmt.Errorf("failed to scan country stats: %w", err)
+		}
+		s.LastSuccessAt = timeOrNil(lastSuccessAt)
+		result = append(result, s)
+	}
+	return result, rows.Err()
+}
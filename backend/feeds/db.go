@@ -46,29 +46,196 @@ func InitDB() error {
 		// 🔍 Print the DB path being used
 		fmt.Printf("📂 Opening DB at: %s\n", dbPath)
 
-		db, err = sql.Open("sqlite", dbPath)
-		if err != nil {
-			err = fmt.Errorf("failed to open database: %w", err)
-			return
-		}
-
-		if err = db.Ping(); err != nil {
-			err = fmt.Errorf("failed to ping database: %w", err)
-			return
-		}
-
-		createTable := `
-			CREATE TABLE IF NOT EXISTS feeds (
-				country TEXT PRIMARY KEY,
-				urls TEXT NOT NULL
-			);`
-		if _, err = db.Exec(createTable); err != nil {
-			err = fmt.Errorf("failed to create feeds table: %w", err)
-		}
+		// Wait on locks instead of failing, since the poller writes concurrently
+		err = Open("file:" + dbPath + "?_pragma=busy_timeout(5000)")
 	})
 	return err
 }
 
+// Open connects to the SQLite database at dsn and creates or migrates its tables,
+// replacing any database opened before. InitDB opens the configured database file;
+// tests open private in-memory databases ("file:name?mode=memory&cache=shared").
+func Open(dsn string) (err error) {
+	if db != nil {
+		db.Close()
+	}
+	db, err = sql.Open("sqlite", dsn)
+	if err != nil {
+		err = fmt.Errorf("failed to open database: %w", err)
+		return
+	}
+
+	if err = db.Ping(); err != nil {
+		err = fmt.Errorf("failed to ping database: %w", err)
+		return
+	}
+
+	createSources := `
+		CREATE TABLE IF NOT EXISTS feed_sources (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			country TEXT NOT NULL,
+			url TEXT NOT NULL,
+			title TEXT NOT NULL DEFAULT '',
+			language TEXT NOT NULL DEFAULT '',
+			enabled INTEGER NOT NULL DEFAULT 1,
+			priority INTEGER NOT NULL DEFAULT 0,
+			added_by TEXT NOT NULL DEFAULT '',
+			notes TEXT NOT NULL DEFAULT '',
+			created_at INTEGER NOT NULL,
+			updated_at INTEGER NOT NULL,
+			UNIQUE (country, url)
+		);`
+	if _, err = db.Exec(createSources); err != nil {
+		err = fmt.Errorf("failed to create feed_sources table: %w", err)
+		return
+	}
+
+	if err = ensureColumns("feed_sources", []string{
+		"extract_content INTEGER NOT NULL DEFAULT 0",
+		"keywords TEXT NOT NULL DEFAULT '[]'",
+	}); err != nil {
+		return
+	}
+
+	if err = migrateLegacyFeeds(); err != nil {
+		err = fmt.Errorf("failed to migrate legacy feeds: %w", err)
+		return
+	}
+
+	createArticles := `
+		CREATE TABLE IF NOT EXISTS articles (
+			id TEXT PRIMARY KEY,
+			feed_url TEXT NOT NULL,
+			guid TEXT NOT NULL DEFAULT '',
+			link TEXT NOT NULL DEFAULT '',
+			title TEXT NOT NULL DEFAULT '',
+			description TEXT NOT NULL DEFAULT '',
+			published TEXT NOT NULL DEFAULT '',
+			published_at INTEGER,
+			source TEXT NOT NULL DEFAULT '',
+			first_seen_at INTEGER NOT NULL,
+			updated_at INTEGER NOT NULL
+		);
+		CREATE INDEX IF NOT EXISTS idx_articles_feed ON articles (feed_url, published_at DESC);
+		CREATE INDEX IF NOT EXISTS idx_articles_updated ON articles (updated_at);`
+	if _, err = db.Exec(createArticles); err != nil {
+		err = fmt.Errorf("failed to create articles table: %w", err)
+		return
+	}
+	if err = ensureColumns("articles", []string{
+		"image TEXT NOT NULL DEFAULT ''",
+		"authors TEXT NOT NULL DEFAULT '[]'",
+		"categories TEXT NOT NULL DEFAULT '[]'",
+		"enclosures TEXT NOT NULL DEFAULT '[]'",
+		"item_updated TEXT NOT NULL DEFAULT ''",
+		"item_updated_at INTEGER",
+		"lead TEXT NOT NULL DEFAULT ''",
+		"reading_minutes INTEGER NOT NULL DEFAULT 0",
+		"extracted_at INTEGER",
+		"language TEXT NOT NULL DEFAULT ''",
+	}); err != nil {
+		return
+	}
+
+	createFeedState := `
+		CREATE TABLE IF NOT EXISTS feed_state (
+			url TEXT PRIMARY KEY,
+			etag TEXT NOT NULL DEFAULT '',
+			last_modified TEXT NOT NULL DEFAULT '',
+			checked_at INTEGER,
+			changed_at INTEGER
+		);`
+	if _, err = db.Exec(createFeedState); err != nil {
+		err = fmt.Errorf("failed to create feed_state table: %w", err)
+		return
+	}
+	if err = ensureColumns("feed_state", []string{
+		"consecutive_failures INTEGER NOT NULL DEFAULT 0",
+		"last_error TEXT NOT NULL DEFAULT ''",
+		"last_error_at INTEGER",
+		"next_retry_at INTEGER",
+		"language TEXT NOT NULL DEFAULT ''",
+	}); err != nil {
+		return
+	}
+
+	createFeedErrors := `
+		CREATE TABLE IF NOT EXISTS feed_errors (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			url TEXT NOT NULL,
+			error TEXT NOT NULL,
+			occurred_at INTEGER NOT NULL
+		);
+		CREATE INDEX IF NOT EXISTS idx_feed_errors_url ON feed_errors (url, occurred_at DESC);`
+	if _, err = db.Exec(createFeedErrors); err != nil {
+		err = fmt.Errorf("failed to create feed_errors table: %w", err)
+		return
+	}
+
+	createTranslations := `
+		CREATE TABLE IF NOT EXISTS translations (
+			key TEXT PRIMARY KEY,
+			source_lang TEXT NOT NULL,
+			target_lang TEXT NOT NULL,
+			source_text TEXT NOT NULL,
+			translated_text TEXT NOT NULL,
+			provider TEXT NOT NULL DEFAULT '',
+			hits INTEGER NOT NULL DEFAULT 0,
+			created_at INTEGER NOT NULL,
+			last_used_at INTEGER NOT NULL
+		);
+		CREATE INDEX IF NOT EXISTS idx_translations_last_used ON translations (last_used_at);`
+	if _, err = db.Exec(createTranslations); err != nil {
+		err = fmt.Errorf("failed to create translations table: %w", err)
+		return
+	}
+
+	createTranslationUsage := `
+		CREATE TABLE IF NOT EXISTS translation_usage (
+			provider TEXT NOT NULL,
+			day TEXT NOT NULL,
+			characters INTEGER NOT NULL DEFAULT 0,
+			requests INTEGER NOT NULL DEFAULT 0,
+			PRIMARY KEY (provider, day)
+		);`
+	if _, err = db.Exec(createTranslationUsage); err != nil {
+		err = fmt.Errorf("failed to create translation_usage table: %w", err)
+		return
+	}
+
+	createRegions := `
+		CREATE TABLE IF NOT EXISTS regions (
+			kind TEXT NOT NULL,
+			code TEXT NOT NULL,
+			name TEXT NOT NULL DEFAULT '',
+			countries TEXT NOT NULL DEFAULT '[]',
+			updated_at INTEGER NOT NULL,
+			PRIMARY KEY (kind, code)
+		);`
+	if _, err = db.Exec(createRegions); err != nil {
+		err = fmt.Errorf("failed to create regions table: %w", err)
+		return
+	}
+	if err = seedRegions(); err != nil {
+		err = fmt.Errorf("failed to seed regions: %w", err)
+		return
+	}
+
+	// Plain text of original and translated articles; filled on ingestion and translation
+	createSearchIndex := `
+		CREATE VIRTUAL TABLE IF NOT EXISTS articles_fts USING fts5(
+			article_id UNINDEXED,
+			lang UNINDEXED,
+			kind UNINDEXED,
+			title,
+			description,
+			tokenize = 'unicode61 remove_diacritics 2'
+		);`
+	if _, err = db.Exec(createSearchIndex); err != nil {
+		err = fmt.Errorf("failed to create articles_fts table: %w", err)
+	}
+	return
+}
 
 func copyFile(src, dst string) error {
 	in, err := os.Open(src)
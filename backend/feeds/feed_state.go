@@ -0,0 +1,212 @@
+package feeds
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// errorHistoryPerFeed bounds how many past errors are kept for each feed URL.
+const errorHistoryPerFeed = 10
+
+// FeedState holds what we remember about the fetches of a feed URL: the HTTP
+// validators used for conditional requests and its health.
+type FeedState struct {
+	URL                 string     `json:"url"`
+	ETag                string     `json:"etag,omitempty"`
+	LastModified        string     `json:"lastModified,omitempty"`
+	CheckedAt           *time.Time `json:"lastSuccessAt,omitempty"` // last successful fetch, changed or not
+	ChangedAt           *time.Time `json:"lastChangedAt,omitempty"` // last fetch that returned a new body
+	ConsecutiveFailures int        `json:"consecutiveFailures"`
+	LastError           string     `json:"lastError,omitempty"`
+	LastErrorAt         *time.Time `json:"lastErrorAt,omitempty"`
+	NextRetryAt         *time.Time `json:"nextRetryAt,omitempty"`
+	Language            string     `json:"language,omitempty"` // declared or detected on the last changed fetch
+}
+
+// FeedError is a single recorded fetch failure.
+type FeedError struct {
+	Error      string    `json:"error"`
+	OccurredAt time.Time `json:"occurredAt"`
+}
+
+const feedStateColumns = `url, etag, last_modified, checked_at, changed_at, consecutive_failures, last_error, last_error_at, next_retry_at, language`
+
+// GetFeedState returns the stored state for a feed URL.
+// A feed that was never fetched yields a zero state and no error.
+func GetFeedState(url string) (FeedState, error) {
+	rows, err := db.Query(`SELECT `+feedStateColumns+` FROM feed_state WHERE url = ?`, url)
+	if err != nil {
+		return FeedState{URL: url}, fmt.Errorf("query error: %w", err)
+	}
+	defer rows.Close()
+
+	states, err := scanFeedStates(rows)
+	if err != nil || len(states) == 0 {
+		return FeedState{URL: url}, err
+	}
+	return states[0], nil
+}
+
+// ListUnhealthyFeeds returns the state of every feed whose last fetch failed,
+// worst first. With includeHealthy set, all known feeds are returned.
+func ListUnhealthyFeeds(includeHealthy bool) ([]FeedState, error) {
+	query := `SELECT ` + feedStateColumns + ` FROM feed_state`
+	if !includeHealthy {
+		query += ` WHERE consecutive_failures > 0`
+	}
+	query += ` ORDER BY consecutive_failures DESC, url ASC`
+
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	defer rows.Close()
+
+	return scanFeedStates(rows)
+}
+
+// GetFeedErrors returns the most recent recorded errors of a feed URL, newest first.
+func GetFeedErrors(url string) ([]FeedError, error) {
+	rows, err := db.Query(`
+		SELECT error, occurred_at
+		FROM feed_errors
+		WHERE url = ?
+		ORDER BY occurred_at DESC, id DESC
+		LIMIT ?
+	`, url, errorHistoryPerFeed)
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	defer rows.Close()
+
+	var result []FeedError
+	for rows.Next() {
+		var (
+			e          FeedError
+			occurredAt int64
+		)
+		if err := rows.Scan(&e.Error, &occurredAt); err != nil {
+			return nil, fmt.Errorf("failed to scan feed error: %w", err)
+		}
+		e.OccurredAt = time.Unix(occurredAt, 0).UTC()
+		result = append(result, e)
+	}
+	return result, rows.Err()
+}
+
+// MarkFeedChanged records a fetch that returned a new body, along with its validators
+// and the feed's language, and resets the feed's failure streak.
+func MarkFeedChanged(url, etag, lastModified, language string) error {
+	now := time.Now().Unix()
+	_, err := db.Exec(`
+		INSERT INTO feed_state (url, etag, last_modified, checked_at, changed_at, language)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(url) DO UPDATE SET
+			etag = excluded.etag,
+			last_modified = excluded.last_modified,
+			checked_at = excluded.checked_at,
+			changed_at = excluded.changed_at,
+			language = excluded.language,
+			consecutive_failures = 0,
+			next_retry_at = NULL
+	`, url, etag, lastModified, now, now, language)
+	if err != nil {
+		return fmt.Errorf("failed to save feed state for %s: %w", url, err)
+	}
+	return nil
+}
+
+// MarkFeedUnchanged records a successful fetch that returned 304 Not Modified.
+func MarkFeedUnchanged(url string) error {
+	_, err := db.Exec(`
+		INSERT INTO feed_state (url, checked_at)
+		VALUES (?, ?)
+		ON CONFLICT(url) DO UPDATE SET
+			checked_at = excluded.checked_at,
+			consecutive_failures = 0,
+			next_retry_at = NULL
+	`, url, time.Now().Unix())
+	if err != nil {
+		return fmt.Errorf("failed to save feed state for %s: %w", url, err)
+	}
+	return nil
+}
+
+// MarkFeedFailed records a failed fetch, extends the failure streak and stores the
+// time before which the feed should not be retried. The error is added to the
+// feed's history, which is trimmed to the most recent entries.
+func MarkFeedFailed(url string, fetchErr error, nextRetry time.Time) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now().Unix()
+	msg := fetchErr.Error()
+
+	if _, err := tx.Exec(`
+		INSERT INTO feed_state (url, consecutive_failures, last_error, last_error_at, next_retry_at)
+		VALUES (?, 1, ?, ?, ?)
+		ON CONFLICT(url) DO UPDATE SET
+			consecutive_failures = consecutive_failures + 1,
+			last_error = excluded.last_error,
+			last_error_at = excluded.last_error_at,
+			next_retry_at = excluded.next_retry_at
+	`, url, msg, now, nextRetry.Unix()); err != nil {
+		return fmt.Errorf("failed to save feed state for %s: %w", url, err)
+	}
+
+	if _, err := tx.Exec(
+		`INSERT INTO feed_errors (url, error, occurred_at) VALUES (?, ?, ?)`,
+		url, msg, now,
+	); err != nil {
+		return fmt.Errorf("failed to record error for %s: %w", url, err)
+	}
+	if _, err := tx.Exec(`
+		DELETE FROM feed_errors
+		WHERE url = ? AND id NOT IN (
+			SELECT id FROM feed_errors WHERE url = ? ORDER BY occurred_at DESC, id DESC LIMIT ?
+		)
+	`, url, url, errorHistoryPerFeed); err != nil {
+		return fmt.Errorf("failed to trim errors for %s: %w", url, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to save feed state for %s: %w", url, err)
+	}
+	return nil
+}
+
+// scanFeedStates reads all rows of a feed_state query selecting feedStateColumns.
+func scanFeedStates(rows *sql.Rows) ([]FeedState, error) {
+	var result []FeedState
+	for rows.Next() {
+		var (
+			s                                              FeedState
+			checkedAt, changedAt, lastErrorAt, nextRetryAt sql.NullInt64
+		)
+		if err := rows.Scan(
+			&s.URL, &s.ETag, &s.LastModified, &checkedAt, &changedAt,
+			&s.ConsecutiveFailures, &s.LastError, &lastErrorAt, &nextRetryAt, &s.Language,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan feed state: %w", err)
+		}
+		s.CheckedAt = timeOrNil(checkedAt)
+		s.ChangedAt = timeOrNil(changedAt)
+		s.LastErrorAt = timeOrNil(lastErrorAt)
+		s.NextRetryAt = timeOrNil(nextRetryAt)
+		result = append(result, s)
+	}
+	return result, rows.Err()
+}
+
+// timeOrNil converts a nullable SQLite integer into an optional UTC time.
+func timeOrNil(v sql.NullInt64) *time.Time {
+	if !v.Valid {
+		return nil
+	}
+	t := time.Unix(v.Int64, 0).UTC()
+	return &t
+}
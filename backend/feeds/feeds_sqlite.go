@@ -5,85 +5,280 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 )
 
 // FeedConfig represents the JSON structure used for configuring RSS feeds by country.
+// Besides ISO country codes, CountryCode can name another channel such as an ocean ID.
+// Feeds is the plain URL list; Sources carries the full per-feed metadata and,
+// when present, takes precedence over Feeds on writes.
 type FeedConfig struct {
-	CountryCode string   `json:"country"`
-	Feeds       []string `json:"feeds"`
+	CountryCode string       `json:"country"`
+	Feeds       []string     `json:"feeds"`
+	Sources     []FeedSource `json:"sources,omitempty"`
+}
+
+// FeedSource is a single feed URL attached to a country, with its metadata.
+// Higher priorities are preferred; ties are broken by insertion order.
+type FeedSource struct {
+	ID       int64  `json:"id,omitempty"`
+	Country  string `json:"country,omitempty"`
+	URL      string `json:"url"`
+	Title    string `json:"title,omitempty"`
+	Language string `json:"language,omitempty"`
+	Enabled  bool   `json:"enabled"`
+	Priority int    `json:"priority"`
+	AddedBy  string `json:"addedBy,omitempty"`
+	Notes    string `json:"notes,omitempty"`
+	// ExtractContent fetches article pages to fill in empty or truncated descriptions
+	ExtractContent bool `json:"extractContent,omitempty"`
+	// Keywords restrict the feed to articles mentioning any of them, e.g. to take only the
+	// shipping news of a general feed. An empty list keeps every article.
+	Keywords  []string  `json:"keywords,omitempty"`
+	CreatedAt time.Time `json:"createdAt,omitzero"`
+	UpdatedAt time.Time `json:"updatedAt,omitzero"`
+}
+
+// UnmarshalJSON decodes a FeedSource, treating a missing "enabled" field as true.
+func (s *FeedSource) UnmarshalJSON(data []byte) error {
+	type alias FeedSource
+	decoded := alias{Enabled: true}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	*s = FeedSource(decoded)
+	return nil
 }
 
 // ErrNoFeeds is returned when no feeds are found for a country.
 var ErrNoFeeds = errors.New("no feeds found")
 
-// GetFeeds returns all feeds for a given country code.
+const feedSourceColumns = `id, country, url, title, language, enabled, priority, added_by, notes, extract_content, keywords, created_at, updated_at`
+
+// GetFeeds returns the enabled feed URLs for a given country code, in priority order.
 func GetFeeds(country string) ([]string, error) {
-	row := db.QueryRow(`SELECT urls FROM feeds WHERE country = ?`, country)
+	sources, err := GetEnabledFeedSources(country)
+	if err != nil {
+		return nil, err
+	}
 
-	var jsonData string
-	if err := row.Scan(&jsonData); err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, fmt.Errorf("%w for country: %s", ErrNoFeeds, country)
+	urls := make([]string, 0, len(sources))
+	for _, s := range sources {
+		urls = append(urls, s.URL)
+	}
+	return urls, nil
+}
+
+// GetEnabledFeedSources returns the enabled feed sources for a given country code, in priority order.
+func GetEnabledFeedSources(country string) ([]FeedSource, error) {
+	sources, err := GetFeedSources(country)
+	if err != nil {
+		return nil, err
+	}
+
+	var enabled []FeedSource
+	for _, s := range sources {
+		if s.Enabled {
+			enabled = append(enabled, s)
 		}
+	}
+	if len(enabled) == 0 {
+		return nil, fmt.Errorf("%w for country: %s", ErrNoFeeds, country)
+	}
+	return enabled, nil
+}
+
+// GetFeedSources returns all feed sources (enabled or not) for a given country code.
+func GetFeedSources(country string) ([]FeedSource, error) {
+	rows, err := db.Query(`
+		SELECT `+feedSourceColumns+`
+		FROM feed_sources
+		WHERE country = ?
+		ORDER BY priority DESC, id ASC
+	`, country)
+	if err != nil {
 		return nil, fmt.Errorf("query error: %w", err)
 	}
+	defer rows.Close()
 
-	var urls []string
-	if err := json.Unmarshal([]byte(jsonData), &urls); err != nil {
-		return nil, fmt.Errorf("failed to parse feed list: %w", err)
+	sources, err := scanFeedSources(rows)
+	if err != nil {
+		return nil, err
 	}
-	return urls, nil
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("%w for country: %s", ErrNoFeeds, country)
+	}
+	return sources, nil
 }
 
-// SetFeeds inserts or updates the feeds for a given country.
-func SetFeeds(country string, feedsList []string) error {
-	jsonData, err := json.Marshal(feedsList)
+// GetFeedCountry returns the country a feed URL is configured for.
+// Feeds listed under several countries report the first one alphabetically.
+func GetFeedCountry(url string) (string, error) {
+	var country string
+	err := db.QueryRow(`
+		SELECT country FROM feed_sources WHERE url = ? ORDER BY country ASC LIMIT 1
+	`, url).Scan(&country)
 	if err != nil {
-		return fmt.Errorf("failed to marshal feed list: %w", err)
+		return "", fmt.Errorf("query error: %w", err)
+	}
+	return country, nil
+}
+
+// SetFeeds replaces the feed URLs for a given country.
+// Metadata of URLs that are already configured is kept; new URLs are enabled with defaults
+// and addedBy recorded.
+func SetFeeds(country string, feedsList []string, addedBy string) error {
+	existing := make(map[string]FeedSource)
+	if current, err := GetFeedSources(country); err == nil {
+		for _, s := range current {
+			existing[s.URL] = s
+		}
+	} else if !errors.Is(err, ErrNoFeeds) {
+		return err
 	}
 
-	_, err = db.Exec(`
-		INSERT INTO feeds (country, urls)
-		VALUES (?, ?)
-		ON CONFLICT(country) DO UPDATE SET urls = excluded.urls
-	`, country, string(jsonData))
+	sources := make([]FeedSource, 0, len(feedsList))
+	for _, url := range feedsList {
+		if s, ok := existing[url]; ok {
+			sources = append(sources, s)
+		} else {
+			sources = append(sources, FeedSource{URL: url, Enabled: true})
+		}
+	}
+	return SetFeedSources(country, sources, addedBy)
+}
 
+// SetFeedSources replaces the feed sources for a given country.
+// Sources are matched by URL: existing rows are updated, new ones inserted with
+// addedBy recorded, and rows missing from the list are removed.
+func SetFeedSources(country string, sources []FeedSource, addedBy string) error {
+	tx, err := db.Begin()
 	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now().Unix()
+	keep := make([]any, 0, len(sources)+1)
+	keep = append(keep, country)
+
+	for _, s := range sources {
+		if s.URL == "" {
+			continue
+		}
+		if _, err := tx.Exec(`
+			INSERT INTO feed_sources (country, url, title, language, enabled, priority, added_by, notes, extract_content, keywords, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT(country, url) DO UPDATE SET
+				title = excluded.title,
+				language = excluded.language,
+				enabled = excluded.enabled,
+				priority = excluded.priority,
+				notes = excluded.notes,
+				extract_content = excluded.extract_content,
+				keywords = excluded.keywords,
+				updated_at = excluded.updated_at
+		`, country, s.URL, s.Title, s.Language, s.Enabled, s.Priority, addedBy, s.Notes, s.ExtractContent, jsonList(s.Keywords), now, now); err != nil {
+			return fmt.Errorf("failed to save feed %s: %w", s.URL, err)
+		}
+		keep = append(keep, s.URL)
+	}
+
+	query := `DELETE FROM feed_sources WHERE country = ?`
+	if len(keep) > 1 {
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(keep)-1), ", ")
+		query += ` AND url NOT IN (` + placeholders + `)`
+	}
+	if _, err := tx.Exec(query, keep...); err != nil {
+		return fmt.Errorf("failed to remove stale feeds: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
 		return fmt.Errorf("failed to save feeds: %w", err)
 	}
 	return nil
 }
 
-// ListAllFeeds returns a deep copy of all feeds in the database.
+// ListAllFeeds returns the enabled feed URLs of every country.
 func ListAllFeeds() map[string][]string {
-	rows, err := db.Query(`SELECT country, urls FROM feeds`)
+	sources, err := ListAllFeedSources()
 	if err != nil {
 		fmt.Printf("❌ Failed to query feeds: %v\n", err)
 		return nil
 	}
-	defer rows.Close()
 
 	result := make(map[string][]string)
-	for rows.Next() {
-		var country, jsonData string
-		if err := rows.Scan(&country, &jsonData); err != nil {
-			continue
+	for _, s := range sources {
+		if s.Enabled {
+			result[s.Country] = append(result[s.Country], s.URL)
 		}
-		var urls []string
-		if err := json.Unmarshal([]byte(jsonData), &urls); err != nil {
-			continue
-		}
-		result[country] = urls
 	}
 	return result
 }
 
+// ListAllFeedSources returns every feed source, grouped by country and in priority order.
+func ListAllFeedSources() ([]FeedSource, error) {
+	rows, err := db.Query(`
+		SELECT ` + feedSourceColumns + `
+		FROM feed_sources
+		ORDER BY country ASC, priority DESC, id ASC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	defer rows.Close()
+
+	return scanFeedSources(rows)
+}
+
+// ListFeedConfigs returns all feed sources grouped into one FeedConfig per country.
+func ListFeedConfigs() ([]FeedConfig, error) {
+	sources, err := ListAllFeedSources()
+	if err != nil {
+		return nil, err
+	}
+
+	var configs []FeedConfig
+	for _, s := range sources {
+		if len(configs) == 0 || configs[len(configs)-1].CountryCode != s.Country {
+			configs = append(configs, FeedConfig{CountryCode: s.Country})
+		}
+		cfg := &configs[len(configs)-1]
+		cfg.Feeds = append(cfg.Feeds, s.URL)
+		cfg.Sources = append(cfg.Sources, s)
+	}
+	return configs, nil
+}
+
 // DeleteFeeds removes all feeds for a given country code.
 func DeleteFeeds(country string) error {
-	_, err := db.Exec(`DELETE FROM feeds WHERE country = ?`, country)
+	_, err := db.Exec(`DELETE FROM feed_sources WHERE country = ?`, country)
 	if err != nil {
 		return fmt.Errorf("failed to delete feeds for %s: %w", country, err)
 	}
 	return nil
 }
 
+// scanFeedSources reads all rows of a feed_sources query selecting feedSourceColumns.
+func scanFeedSources(rows *sql.Rows) ([]FeedSource, error) {
+	var result []FeedSource
+	for rows.Next() {
+		var (
+			s                    FeedSource
+			keywords             string
+			createdAt, updatedAt int64
+		)
+		if err := rows.Scan(
+			&s.ID, &s.Country, &s.URL, &s.Title, &s.Language, &s.Enabled,
+			&s.Priority, &s.AddedBy, &s.Notes, &s.ExtractContent, &keywords, &createdAt, &updatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan feed source: %w", err)
+		}
+		_ = json.Unmarshal([]byte(keywords), &s.Keywords)
+		s.CreatedAt = time.Unix(createdAt, 0).UTC()
+		s.UpdatedAt = time.Unix(updatedAt, 0).UTC()
+		result = append(result, s)
+	}
+	return result, rows.Err()
+}
@@ -0,0 +1,27 @@
+package feeds
+
+import "testing"
+
+func TestSetFeedsRecordsAddedBy(t *testing.T) {
+	openTestDB(t)
+
+	if err := SetFeeds("DE", []string{"https://a.example/rss"}, "alice"); err != nil {
+		t.Fatal(err)
+	}
+	// Feeds that stay keep who added them; only new ones are credited to bob
+	if err := SetFeeds("DE", []string{"https://a.example/rss", "https://b.example/rss"}, "bob"); err != nil {
+		t.Fatal(err)
+	}
+
+	sources, err := GetFeedSources("DE")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addedBy := make(map[string]string)
+	for _, s := range sources {
+		addedBy[s.URL] = s.AddedBy
+	}
+	if addedBy["https://a.example/rss"] != "alice" || addedBy["https://b.example/rss"] != "bob" {
+		t.Errorf("added_by = %v, want a by alice and b by bob", addedBy)
+	}
+}
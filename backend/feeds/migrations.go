@@ -0,0 +1,110 @@
+package feeds
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// migrateLegacyFeeds moves the old `feeds` table (one JSON array of URLs per country)
+// into feed_sources, one row per URL. The old table is kept as `feeds_legacy` so the
+// migration only ever runs once.
+func migrateLegacyFeeds() error {
+	var exists int
+	if err := db.QueryRow(
+		`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'feeds'`,
+	).Scan(&exists); err != nil {
+		return fmt.Errorf("query error: %w", err)
+	}
+	if exists == 0 {
+		return nil
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`SELECT country, urls FROM feeds`)
+	if err != nil {
+		return fmt.Errorf("query error: %w", err)
+	}
+
+	legacy := make(map[string][]string)
+	for rows.Next() {
+		var country, jsonData string
+		if err := rows.Scan(&country, &jsonData); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan legacy row: %w", err)
+		}
+		var urls []string
+		if err := json.Unmarshal([]byte(jsonData), &urls); err != nil {
+			log.Printf("⚠️ Skipping unreadable legacy feeds for %s: %v", country, err)
+			continue
+		}
+		legacy[country] = urls
+	}
+	rows.Close()
+
+	now := time.Now().Unix()
+	migrated := 0
+	for country, urls := range legacy {
+		for _, url := range urls {
+			// Keep the original array order through ascending IDs
+			if _, err := tx.Exec(`
+				INSERT OR IGNORE INTO feed_sources (country, url, added_by, created_at, updated_at)
+				VALUES (?, ?, 'migration', ?, ?)
+			`, country, url, now, now); err != nil {
+				return fmt.Errorf("failed to migrate %s for %s: %w", url, country, err)
+			}
+			migrated++
+		}
+	}
+
+	if _, err := tx.Exec(`DROP TABLE IF EXISTS feeds_legacy`); err != nil {
+		return fmt.Errorf("failed to drop old backup table: %w", err)
+	}
+	if _, err := tx.Exec(`ALTER TABLE feeds RENAME TO feeds_legacy`); err != nil {
+		return fmt.Errorf("failed to rename legacy table: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit migration: %w", err)
+	}
+	log.Printf("🪴 Migrated %d feed URLs from %d countries into feed_sources", migrated, len(legacy))
+	return nil
+}
+
+// ensureColumns adds every column definition (e.g. "notes TEXT NOT NULL DEFAULT ''")
+// that is missing from table, so existing databases pick up new fields.
+func ensureColumns(table string, definitions []string) error {
+	rows, err := db.Query(`SELECT name FROM pragma_table_info(?)`, table)
+	if err != nil {
+		return fmt.Errorf("query error: %w", err)
+	}
+	existing := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan column of %s: %w", table, err)
+		}
+		existing[name] = true
+	}
+	rows.Close()
+
+	for _, def := range definitions {
+		name := strings.Fields(def)[0]
+		if existing[name] {
+			continue
+		}
+		if _, err := db.Exec(`ALTER TABLE ` + table + ` ADD COLUMN ` + def); err != nil {
+			return fmt.Errorf("failed to add %s.%s: %w", table, name, err)
+		}
+		log.Printf("🪴 Added column %s.%s", table, name)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package feeds
+
+import (
+	"database/sql"
+	"testing"
+)
+
+func TestMigrateLegacyFeeds(t *testing.T) {
+	dsn := "file:" + t.Name() + "?mode=memory&cache=shared"
+
+	// A database as written before feed_sources existed. The connection keeps the
+	// in-memory database alive while Open migrates it.
+	legacy, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer legacy.Close()
+	if _, err := legacy.Exec(`
+		CREATE TABLE feeds (country TEXT PRIMARY KEY, urls TEXT NOT NULL);
+		INSERT INTO feeds VALUES
+			('DE', '["https://b.example/rss", "https://a.example/rss", "https://b.example/rss"]'),
+			('FR', '["https://c.example/rss"]'),
+			('XX', 'not json');
+	`); err != nil {
+		t.Fatal(err)
+	}
+
+	for range 2 { // the second open finds nothing left to migrate
+		if err := Open(dsn); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	sources, err := GetFeedSources("DE")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sources) != 2 || sources[0].URL != "https://b.example/rss" || sources[1].URL != "https://a.example/rss" {
+		t.Fatalf("DE sources = %+v, want b then a, once each", sources)
+	}
+	for _, s := range sources {
+		if !s.Enabled || s.AddedBy != "migration" {
+			t.Errorf("%s: enabled %v, added by %q", s.URL, s.Enabled, s.AddedBy)
+		}
+	}
+	if urls, err := GetFeeds("FR"); err != nil || len(urls) != 1 {
+		t.Errorf("FR feeds = %v, %v", urls, err)
+	}
+
+	var tables int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name IN ('feeds', 'feeds_legacy')`).Scan(&tables); err != nil {
+		t.Fatal(err)
+	}
+	var kept string
+	if err := db.QueryRow(`SELECT urls FROM feeds_legacy WHERE country = 'XX'`).Scan(&kept); err != nil || tables != 1 {
+		t.Errorf("the legacy table should be kept as feeds_legacy only (%d tables, %v)", tables, err)
+	}
+}
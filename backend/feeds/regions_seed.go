@@ -0,0 +1,22 @@
+package feeds
+
+import "strings"
+
+// defaultRegions seed the region registry: the continents by ISO 3166-1 alpha-2 code
+// (following the UN geoscheme) and a few commonly selected groups.
+var defaultRegions = []Region{
+	continent("AF", "Africa", "DZ AO BJ BW BF BI CV CM CF TD KM CG CD CI DJ EG GQ ER SZ ET GA GM GH GN GW KE LS LR LY MG MW ML MR MU YT MA MZ NA NE NG RE RW SH ST SN SC SL SO ZA SS SD TZ TG TN UG EH ZM ZW"),
+	continent("AN", "Antarctica", "AQ BV GS HM TF"),
+	continent("AS", "Asia", "AF AM AZ BH BD BT BN KH CN CY GE HK IN ID IR IQ IL JP JO KZ KW KG LA LB MO MY MV MN MM NP KP OM PK PS PH QA SA SG KR LK SY TW TJ TH TL TR TM AE UZ VN YE"),
+	continent("EU", "Europe", "AX AL AD AT BY BE BA BG HR CZ DK EE FO FI FR DE GI GR GG HU IS IE IM IT JE XK LV LI LT LU MT MD MC ME NL MK NO PL PT RO RU SM RS SK SI ES SJ SE CH UA GB VA"),
+	continent("NA", "North America", "AI AG AW BS BB BZ BM BQ VG CA KY CR CU CW DM DO SV GL GD GP GT HT HN JM MQ MX MS NI PA PR BL KN LC MF PM VC SX TT TC US VI UM"),
+	continent("OC", "Oceania", "AS AU CK FJ PF GU KI MH FM NR NC NZ NU NF MP PW PG PN WS SB TK TO TV VU WF"),
+	continent("SA", "South America", "AR BO BR CL CO EC FK GF GY PY PE SR UY VE"),
+	{Kind: RegionKindRegion, Code: "EU", Name: "European Union", Countries: strings.Fields("AT BE BG HR CY CZ DK EE FI FR DE GR HU IE IT LV LT LU MT NL PL PT RO SK SI ES SE")},
+	{Kind: RegionKindRegion, Code: "NORDIC", Name: "Nordic countries", Countries: strings.Fields("DK FI IS NO SE")},
+}
+
+// continent builds a continent entry from a space-separated country list.
+func continent(code, name, countries string) Region {
+	return Region{Kind: RegionKindContinent, Code: code, Name: name, Countries: strings.Fields(countries)}
+}
@@ -0,0 +1,138 @@
+package feeds
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Region kinds. Codes are unique per kind, so "EU" can be both the European Union
+// region and the Europe continent.
+const (
+	RegionKindRegion    = "region"
+	RegionKindContinent = "continent"
+)
+
+// ErrUnknownRegion is returned when a region or continent code is not in the registry.
+var ErrUnknownRegion = errors.New("unknown region")
+
+// Region is a named group of countries that /api/news can be queried by.
+type Region struct {
+	Kind      string    `json:"kind"`
+	Code      string    `json:"code"`
+	Name      string    `json:"name"`
+	Countries []string  `json:"countries"`
+	UpdatedAt time.Time `json:"updatedAt,omitzero"`
+}
+
+// GetRegion returns a region or continent by code.
+func GetRegion(kind, code string) (Region, error) {
+	var (
+		r         = Region{Kind: kind}
+		countries string
+		updatedAt int64
+	)
+	err := db.QueryRow(`
+		SELECT code, name, countries, updated_at FROM regions WHERE kind = ? AND code = ?
+	`, kind, strings.ToUpper(code)).Scan(&r.Code, &r.Name, &countries, &updatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Region{}, fmt.Errorf("%w: %s %s", ErrUnknownRegion, kind, code)
+	}
+	if err != nil {
+		return Region{}, fmt.Errorf("query error: %w", err)
+	}
+	if err := json.Unmarshal([]byte(countries), &r.Countries); err != nil {
+		return Region{}, fmt.Errorf("invalid countries of %s %s: %w", kind, code, err)
+	}
+	r.UpdatedAt = time.Unix(updatedAt, 0).UTC()
+	return r, nil
+}
+
+// ListRegions returns every region and continent, ordered by kind and code.
+func ListRegions() ([]Region, error) {
+	rows, err := db.Query(`SELECT kind, code, name, countries, updated_at FROM regions ORDER BY kind, code`)
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	defer rows.Close()
+
+	var regions []Region
+	for rows.Next() {
+		var (
+			r         Region
+			countries string
+			updatedAt int64
+		)
+		if err := rows.Scan(&r.Kind, &r.Code, &r.Name, &countries, &updatedAt); err != nil {
+			return nil, fmt.Errorf("scan error: %w", err)
+		}
+		if err := json.Unmarshal([]byte(countries), &r.Countries); err != nil {
+			return nil, fmt.Errorf("invalid countries of %s %s: %w", r.Kind, r.Code, err)
+		}
+		r.UpdatedAt = time.Unix(updatedAt, 0).UTC()
+		regions = append(regions, r)
+	}
+	return regions, rows.Err()
+}
+
+// SaveRegion creates or replaces a region. Codes are stored upper-case.
+func SaveRegion(r Region) error {
+	if r.Kind != RegionKindRegion && r.Kind != RegionKindContinent {
+		return fmt.Errorf("invalid region kind %q", r.Kind)
+	}
+
+	countries := make([]string, 0, len(r.Countries))
+	for _, c := range r.Countries {
+		if c = strings.ToUpper(strings.TrimSpace(c)); c != "" {
+			countries = append(countries, c)
+		}
+	}
+
+	_, err := db.Exec(`
+		INSERT INTO regions (kind, code, name, countries, updated_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(kind, code) DO UPDATE SET
+			name = excluded.name,
+			countries = excluded.countries,
+			updated_at = excluded.updated_at
+	`, r.Kind, strings.ToUpper(r.Code), r.Name, jsonList(countries), time.Now().Unix())
+	if err != nil {
+		return fmt.Errorf("failed to save region %s: %w", r.Code, err)
+	}
+	return nil
+}
+
+// DeleteRegion removes a region from the registry.
+func DeleteRegion(kind, code string) error {
+	res, err := db.Exec(`DELETE FROM regions WHERE kind = ? AND code = ?`, kind, strings.ToUpper(code))
+	if err != nil {
+		return fmt.Errorf("failed to delete region %s: %w", code, err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("%w: %s %s", ErrUnknownRegion, kind, code)
+	}
+	return nil
+}
+
+// seedRegions fills an empty registry with the default continents and regions.
+// Once admins have edited the registry, their changes are left alone.
+func seedRegions() error {
+	var count int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM regions`).Scan(&count); err != nil {
+		return fmt.Errorf("query error: %w", err)
+	}
+	if count > 0 {
+		return nil
+	}
+
+	for _, r := range defaultRegions {
+		if err := SaveRegion(r); err != nil {
+			return err
+		}
+	}
+	fmt.Printf("🗺️  Seeded %d default regions\n", len(defaultRegions))
+	return nil
+}
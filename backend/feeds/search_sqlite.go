@@ -0,0 +1,231 @@
+package feeds
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Kinds of text indexed for an article.
+const (
+	SearchOriginal   = "original"
+	SearchTranslated = "translated"
+)
+
+// Highlight markers around matched terms in search snippets. They are control characters,
+// so callers can escape the snippet text before turning them into markup.
+const (
+	SnippetMatchStart = "\x02"
+	SnippetMatchEnd   = "\x03"
+)
+
+// SearchDocument is the plain text of an article in one language, as indexed for search.
+type SearchDocument struct {
+	ArticleID   string
+	Kind        string // SearchOriginal or SearchTranslated
+	Language    string // lower-case ISO 639-1 code, empty if unknown
+	Title       string
+	Description string
+}
+
+// SearchQuery filters and pages a full-text search.
+type SearchQuery struct {
+	Match     string // FTS5 match expression
+	Countries []string
+	Language  string // only match text in this language
+	From      *time.Time
+	To        *time.Time
+	Limit     int
+	Offset    int
+}
+
+// SearchHit is an article matching a search, with highlighted snippets of the best-matching text.
+type SearchHit struct {
+	ArticleID          string
+	Country            string
+	Title              string
+	Link               string
+	Source             string
+	PublishedAt        *time.Time
+	Language           string // language of the original article
+	MatchKind          string // whether the original or a translation matched
+	MatchLanguage      string
+	TitleSnippet       string
+	DescriptionSnippet string
+	Score              float64 // lower is better
+}
+
+// CountryCount is the number of matching articles of a country.
+type CountryCount struct {
+	Country string `json:"country"`
+	Count   int    `json:"count"`
+}
+
+// IndexDocuments replaces the indexed text of articles. Each article keeps one original
+// document, replaced even when its language was corrected, and one translated document
+// per language.
+func IndexDocuments(docs []SearchDocument) error {
+	if len(docs) == 0 {
+		return nil
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, d := range docs {
+		unindex := `DELETE FROM articles_fts WHERE article_id = ? AND kind = ? AND lang = ?`
+		args := []any{d.ArticleID, d.Kind, d.Language}
+		if d.Kind == SearchOriginal {
+			// An article has one original text, whatever language it was indexed under
+			unindex, args = `DELETE FROM articles_fts WHERE article_id = ? AND kind = ?`, args[:2]
+		}
+		if _, err := tx.Exec(unindex, args...); err != nil {
+			return fmt.Errorf("failed to unindex article %s: %w", d.ArticleID, err)
+		}
+		if _, err := tx.Exec(`
+			INSERT INTO articles_fts (article_id, lang, kind, title, description) VALUES (?, ?, ?, ?, ?)
+		`, d.ArticleID, d.Language, d.Kind, d.Title, d.Description); err != nil {
+			return fmt.Errorf("failed to index article %s: %w", d.ArticleID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit search index: %w", err)
+	}
+	return nil
+}
+
+// UnindexedArticles returns up to limit stored articles without an indexed original text.
+func UnindexedArticles(limit int) ([]Article, error) {
+	rows, err := db.Query(`
+		SELECT `+articleColumns+`
+		FROM articles
+		WHERE id NOT IN (SELECT article_id FROM articles_fts WHERE kind = ?)
+		LIMIT ?
+	`, SearchOriginal, limit)
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	defer rows.Close()
+
+	return scanArticles(rows)
+}
+
+// recencyHalfLife is the article age at which relevance counts half.
+const recencyHalfLife = 3 * 24 * time.Hour
+
+// SearchArticles runs a full-text search over original and translated article text.
+// Hits are ranked by BM25 relevance (titles weigh more than descriptions), discounted
+// by article age, and every article appears once with its best-matching text.
+// It returns the requested page, the total number of matching articles and their
+// distribution over countries.
+func SearchArticles(q SearchQuery) ([]SearchHit, int, []CountryCount, error) {
+	var (
+		filters []string
+		args    = []any{
+			SnippetMatchStart, SnippetMatchEnd, SnippetMatchStart, SnippetMatchEnd, q.Match,
+			time.Now().Unix(), recencyHalfLife.Seconds(),
+		}
+	)
+	if len(q.Countries) > 0 {
+		filters = append(filters, `EXISTS (SELECT 1 FROM feed_sources fs WHERE fs.url = a.feed_url AND fs.country IN (`+placeholders(len(q.Countries))+`))`)
+		args = append(args, stringArgs(q.Countries)...)
+	}
+	if q.Language != "" {
+		filters = append(filters, `h.lang = ?`)
+		args = append(args, strings.ToLower(q.Language))
+	}
+	if q.From != nil {
+		filters = append(filters, `COALESCE(a.published_at, a.first_seen_at) >= ?`)
+		args = append(args, q.From.Unix())
+	}
+	if q.To != nil {
+		filters = append(filters, `COALESCE(a.published_at, a.first_seen_at) < ?`)
+		args = append(args, q.To.Unix())
+	}
+	where := ""
+	if len(filters) > 0 {
+		where = "WHERE " + strings.Join(filters, " AND ")
+	}
+
+	matches := `
+		WITH hits AS (
+			SELECT article_id, lang, kind,
+				bm25(articles_fts, 0, 0, 0, 10.0, 1.0) AS relevance,
+				snippet(articles_fts, 3, ?, ?, '…', 16) AS title_snippet,
+				snippet(articles_fts, 4, ?, ?, '…', 32) AS description_snippet
+			FROM articles_fts
+			WHERE articles_fts MATCH ?
+		),
+		ranked AS (
+			SELECT h.*, a.title, a.link, a.source, a.published_at, a.language,
+				(SELECT MIN(fs.country) FROM feed_sources fs WHERE fs.url = a.feed_url) AS country,
+				h.relevance / (1.0 + MAX(0, ? - COALESCE(a.published_at, a.first_seen_at)) / ?) AS score,
+				ROW_NUMBER() OVER (PARTITION BY h.article_id ORDER BY h.relevance) AS n
+			FROM hits h
+			JOIN articles a ON a.id = h.article_id
+			` + where + `
+		)`
+
+	rows, err := db.Query(matches+`
+		SELECT article_id, COALESCE(country, ''), title, link, source, published_at, language,
+			kind, lang, title_snippet, description_snippet, score
+		FROM ranked
+		WHERE n = 1
+		ORDER BY score ASC, published_at DESC
+		LIMIT ? OFFSET ?
+	`, append(args, q.Limit, q.Offset)...)
+	if err != nil {
+		return nil, 0, nil, fmt.Errorf("search error: %w", err)
+	}
+	defer rows.Close()
+
+	var hits []SearchHit
+	for rows.Next() {
+		var (
+			h         SearchHit
+			published *int64
+		)
+		if err := rows.Scan(&h.ArticleID, &h.Country, &h.Title, &h.Link, &h.Source, &published, &h.Language,
+			&h.MatchKind, &h.MatchLanguage, &h.TitleSnippet, &h.DescriptionSnippet, &h.Score); err != nil {
+			return nil, 0, nil, fmt.Errorf("scan error: %w", err)
+		}
+		if published != nil {
+			t := time.Unix(*published, 0).UTC()
+			h.PublishedAt = &t
+		}
+		hits = append(hits, h)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, nil, fmt.Errorf("search error: %w", err)
+	}
+
+	countryRows, err := db.Query(matches+`
+		SELECT COALESCE(country, ''), COUNT(*)
+		FROM ranked
+		WHERE n = 1
+		GROUP BY country
+		ORDER BY COUNT(*) DESC, country ASC
+	`, args...)
+	if err != nil {
+		return nil, 0, nil, fmt.Errorf("search error: %w", err)
+	}
+	defer countryRows.Close()
+
+	var (
+		countries []CountryCount
+		total     int
+	)
+	for countryRows.Next() {
+		var c CountryCount
+		if err := countryRows.Scan(&c.Country, &c.Count); err != nil {
+			return nil, 0, nil, fmt.Errorf("scan error: %w", err)
+		}
+		total += c.Count
+		countries = append(countries, c)
+	}
+	return hits, total, countries, countryRows.Err()
+}
@@ -0,0 +1,132 @@
+package feeds
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// indexTestArticles stores articles of one feed with their original text indexed.
+func indexTestArticles(t *testing.T, articles ...Article) {
+	t.Helper()
+	if err := UpsertArticles(articles); err != nil {
+		t.Fatal(err)
+	}
+	docs := make([]SearchDocument, len(articles))
+	for i, a := range articles {
+		docs[i] = SearchDocument{ArticleID: a.ID, Kind: SearchOriginal, Language: a.Language, Title: a.Title, Description: a.Description}
+	}
+	if err := IndexDocuments(docs); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestIndexDocumentsReplacesOriginalInAnotherLanguage(t *testing.T) {
+	openTestDB(t)
+	now := time.Now()
+	indexTestArticles(t, Article{ID: "a", FeedURL: "https://a.example/rss", Title: "Wildfire near the coast", PublishedAt: &now, Language: "de"})
+
+	// Language detection later finds the article to be English
+	if err := IndexDocuments([]SearchDocument{{ArticleID: "a", Kind: SearchOriginal, Language: "en", Title: "Wildfire near the coast"}}); err != nil {
+		t.Fatal(err)
+	}
+	// Translations are kept per language
+	if err := IndexDocuments([]SearchDocument{
+		{ArticleID: "a", Kind: SearchTranslated, Language: "de", Title: "Waldbrand an der Küste"},
+		{ArticleID: "a", Kind: SearchTranslated, Language: "fr", Title: "Incendie près de la côte"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	var originals, translations int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM articles_fts WHERE article_id = 'a' AND kind = ?`, SearchOriginal).Scan(&originals); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.QueryRow(`SELECT COUNT(*) FROM articles_fts WHERE article_id = 'a' AND kind = ?`, SearchTranslated).Scan(&translations); err != nil {
+		t.Fatal(err)
+	}
+	if originals != 1 || translations != 2 {
+		t.Errorf("indexed %d originals and %d translations, want 1 and 2", originals, translations)
+	}
+
+	hits, total, _, err := SearchArticles(SearchQuery{Match: "wildfire", Language: "de", Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 0 || len(hits) != 0 {
+		t.Errorf("the stale German original still matches: %+v", hits)
+	}
+}
+
+func TestSearchArticlesRanking(t *testing.T) {
+	openTestDB(t)
+	for country, url := range map[string]string{"GB": "https://gb.example/rss", "IE": "https://ie.example/rss"} {
+		if err := SetFeedSources(country, []FeedSource{{URL: url, Enabled: true}}, "test"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	now := time.Now()
+	monthAgo := now.Add(-30 * 24 * time.Hour)
+	article := func(id, feed, title, description string, published *time.Time) Article {
+		return Article{ID: id, FeedURL: feed, Title: title, Description: description, Link: "https://news.example/" + id, PublishedAt: published, Language: "en"}
+	}
+	indexTestArticles(t,
+		article("in-title", "https://gb.example/rss", "Harbour strike ends", "Dock workers return after a week.", &now),
+		article("in-description", "https://gb.example/rss", "Dock workers return", "The harbour strike ended after a week.", &now),
+		article("old-in-title", "https://ie.example/rss", "Harbour strike ends", "Dock workers return after a week.", &monthAgo),
+		article("unrelated-1", "https://ie.example/rss", "Election results", "Counting continues.", &now),
+		article("unrelated-2", "https://ie.example/rss", "Weather warning", "Storms expected.", &now),
+		article("unrelated-3", "https://gb.example/rss", "Cup final", "A late goal decided it.", &now),
+	)
+	// A German translation of an English article matches in its own language only
+	if err := IndexDocuments([]SearchDocument{{ArticleID: "in-title", Kind: SearchTranslated, Language: "de", Title: "Hafenstreik beendet", Description: "Harbour-Arbeiter kehren zurück."}}); err != nil {
+		t.Fatal(err)
+	}
+
+	hits, total, countries, err := SearchArticles(SearchQuery{Match: "harbour", Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var order []string
+	for _, h := range hits {
+		order = append(order, h.ArticleID)
+	}
+	if want := "in-title in-description old-in-title"; strings.Join(order, " ") != want {
+		t.Errorf("order = %v, want %s (title first, then older articles)", order, want)
+	}
+	if total != 3 {
+		t.Errorf("total = %d, want 3: every article once", total)
+	}
+	if want := []CountryCount{{"GB", 2}, {"IE", 1}}; !slices.Equal(countries, want) {
+		t.Errorf("countries = %v, want %v", countries, want)
+	}
+	if len(hits) > 0 && (hits[0].MatchKind != SearchOriginal || !strings.Contains(hits[0].TitleSnippet, SnippetMatchStart+"Harbour"+SnippetMatchEnd)) {
+		t.Errorf("best hit = %+v, want the highlighted original title", hits[0])
+	}
+
+	hits, total, _, err = SearchArticles(SearchQuery{Match: "harbour", Language: "de", Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 1 || hits[0].MatchKind != SearchTranslated || hits[0].MatchLanguage != "de" || hits[0].Country != "GB" {
+		t.Errorf("German hits = %+v, want only the translation", hits)
+	}
+
+	hits, total, _, err = SearchArticles(SearchQuery{Match: "harbour", Countries: []string{"IE"}, Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 1 || len(hits) != 1 || hits[0].ArticleID != "old-in-title" {
+		t.Errorf("Irish hits = %+v", hits)
+	}
+
+	from := now.Add(-24 * time.Hour)
+	_, total, _, err = SearchArticles(SearchQuery{Match: "harbour", From: &from, Limit: 1, Offset: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 2 {
+		t.Errorf("%d hits since yesterday, want 2", total)
+	}
+}
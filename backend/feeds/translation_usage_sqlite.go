@@ -0,0 +1,40 @@
+package feeds
+
+import (
+	"fmt"
+	"time"
+)
+
+// AddTranslationUsage adds billed characters to a provider's counter for the current UTC day.
+// A negative count returns characters that were reserved but not billed; counters never
+// drop below zero, even when the day changed since the reservation.
+func AddTranslationUsage(provider string, characters int64) error {
+	_, err := db.Exec(`
+		INSERT INTO translation_usage (provider, day, characters, requests)
+		VALUES (?1, ?2, MAX(0, ?3), CASE WHEN ?3 > 0 THEN 1 ELSE 0 END)
+		ON CONFLICT(provider, day) DO UPDATE SET
+			characters = MAX(0, characters + ?3),
+			requests = requests + CASE WHEN ?3 > 0 THEN 1 ELSE 0 END
+	`, provider, time.Now().UTC().Format(time.DateOnly), characters)
+	if err != nil {
+		return fmt.Errorf("failed to record translation usage: %w", err)
+	}
+	return nil
+}
+
+// GetTranslationUsage returns the characters billed by a provider today and in the
+// current calendar month (both UTC).
+func GetTranslationUsage(provider string) (day, month int64, err error) {
+	now := time.Now().UTC()
+	err = db.QueryRow(`
+		SELECT
+			COALESCE(SUM(CASE WHEN day = ? THEN characters END), 0),
+			COALESCE(SUM(characters), 0)
+		FROM translation_usage
+		WHERE provider = ? AND day >= ?
+	`, now.Format(time.DateOnly), provider, now.Format("2006-01")+"-01").Scan(&day, &month)
+	if err != nil {
+		return 0, 0, fmt.Errorf("query error: %w", err)
+	}
+	return day, month, nil
+}
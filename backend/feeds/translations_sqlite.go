@@ -0,0 +1,237 @@
+package feeds
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Translation is a persisted translation of a single text.
+type Translation struct {
+	Key            string    `json:"key"`
+	SourceLang     string    `json:"sourceLang"`
+	TargetLang     string    `json:"targetLang"`
+	SourceText     string    `json:"sourceText"`
+	TranslatedText string    `json:"translatedText"`
+	Provider       string    `json:"provider,omitempty"`
+	Hits           int64     `json:"hits"`
+	CreatedAt      time.Time `json:"createdAt"`
+	LastUsedAt     time.Time `json:"lastUsedAt"`
+}
+
+// TranslationFilter selects translations for listing or purging. Empty fields match everything.
+type TranslationFilter struct {
+	Key          string
+	SourceLang   string
+	TargetLang   string
+	Query        string    // substring of the source or translated text
+	UnusedBefore time.Time // only entries not used since this time
+}
+
+// ErrEmptyFilter is returned by PurgeTranslations when no filter field is set.
+var ErrEmptyFilter = errors.New("refusing to purge without a filter")
+
+const translationColumns = `key, source_lang, target_lang, source_text, translated_text, provider, hits, created_at, last_used_at`
+
+// GetTranslations returns the stored translations for the given keys, indexed by key.
+// Keys without a stored translation are absent from the result.
+func GetTranslations(keys []string) (map[string]string, error) {
+	result := make(map[string]string, len(keys))
+	if len(keys) == 0 {
+		return result, nil
+	}
+
+	rows, err := db.Query(
+		`SELECT key, translated_text FROM translations WHERE key IN (`+placeholders(len(keys))+`)`,
+		stringArgs(keys)...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var key, text string
+		if err := rows.Scan(&key, &text); err != nil {
+			return nil, fmt.Errorf("failed to scan translation: %w", err)
+		}
+		result[key] = text
+	}
+	return result, rows.Err()
+}
+
+// SaveTranslations stores new translations. Existing entries keep their counters.
+func SaveTranslations(translations []Translation) error {
+	if len(translations) == 0 {
+		return nil
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO translations (` + translationColumns + `)
+		VALUES (?, ?, ?, ?, ?, ?, 0, ?, ?)
+		ON CONFLICT(key) DO UPDATE SET
+			translated_text = excluded.translated_text,
+			provider = excluded.provider,
+			last_used_at = excluded.last_used_at
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare insert: %w", err)
+	}
+	defer stmt.Close()
+
+	now := time.Now().Unix()
+	for _, t := range translations {
+		if _, err := stmt.Exec(t.Key, t.SourceLang, t.TargetLang, t.SourceText, t.TranslatedText, t.Provider, now, now); err != nil {
+			return fmt.Errorf("failed to save translation %s: %w", t.Key, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to save translations: %w", err)
+	}
+	return nil
+}
+
+// TouchTranslations adds the given number of cache hits to each key and sets its
+// last-used time to usedAt.
+func TouchTranslations(counts map[string]int, usedAt time.Time) error {
+	if len(counts) == 0 {
+		return nil
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for key, n := range counts {
+		if _, err := tx.Exec(
+			`UPDATE translations SET hits = hits + ?, last_used_at = ? WHERE key = ?`,
+			n, usedAt.Unix(), key,
+		); err != nil {
+			return fmt.Errorf("failed to touch translation %s: %w", key, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to touch translations: %w", err)
+	}
+	return nil
+}
+
+// ListTranslations returns matching translations, most recently used first, together
+// with the total number of matches.
+func ListTranslations(filter TranslationFilter, limit, offset int) ([]Translation, int, error) {
+	where, args := filter.where()
+
+	var total int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM translations`+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("query error: %w", err)
+	}
+
+	rows, err := db.Query(
+		`SELECT `+translationColumns+` FROM translations`+where+
+			` ORDER BY last_used_at DESC, key ASC LIMIT ? OFFSET ?`,
+		append(args, limit, offset)...,
+	)
+	if err != nil {
+		return nil, 0, fmt.Errorf("query error: %w", err)
+	}
+	defer rows.Close()
+
+	var result []Translation
+	for rows.Next() {
+		var (
+			t                   Translation
+			createdAt, lastUsed int64
+		)
+		if err := rows.Scan(
+			&t.Key, &t.SourceLang, &t.TargetLang, &t.SourceText, &t.TranslatedText,
+			&t.Provider, &t.Hits, &createdAt, &lastUsed,
+		); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan translation: %w", err)
+		}
+		t.CreatedAt = time.Unix(createdAt, 0).UTC()
+		t.LastUsedAt = time.Unix(lastUsed, 0).UTC()
+		result = append(result, t)
+	}
+	return result, total, rows.Err()
+}
+
+// PurgeTranslations deletes matching translations and returns how many were removed.
+// An empty filter is rejected with ErrEmptyFilter; use PurgeAllTranslations instead.
+func PurgeTranslations(filter TranslationFilter) (int64, error) {
+	where, args := filter.where()
+	if where == "" {
+		return 0, ErrEmptyFilter
+	}
+	return purgeTranslations(where, args)
+}
+
+// PurgeAllTranslations deletes every stored translation.
+func PurgeAllTranslations() (int64, error) {
+	return purgeTranslations("", nil)
+}
+
+func purgeTranslations(where string, args []any) (int64, error) {
+	res, err := db.Exec(`DELETE FROM translations`+where, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge translations: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+// where builds the WHERE clause for a filter, or "" when it matches everything.
+func (f TranslationFilter) where() (string, []any) {
+	var (
+		conds []string
+		args  []any
+	)
+	if f.Key != "" {
+		conds = append(conds, "key = ?")
+		args = append(args, f.Key)
+	}
+	if f.SourceLang != "" {
+		conds = append(conds, "source_lang = ?")
+		args = append(args, strings.ToUpper(f.SourceLang))
+	}
+	if f.TargetLang != "" {
+		conds = append(conds, "target_lang = ?")
+		args = append(args, strings.ToUpper(f.TargetLang))
+	}
+	if f.Query != "" {
+		conds = append(conds, "(instr(source_text, ?) > 0 OR instr(translated_text, ?) > 0)")
+		args = append(args, f.Query, f.Query)
+	}
+	if !f.UnusedBefore.IsZero() {
+		conds = append(conds, "last_used_at < ?")
+		args = append(args, f.UnusedBefore.Unix())
+	}
+
+	if len(conds) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conds, " AND "), args
+}
+
+// placeholders returns n comma-separated SQL placeholders.
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?,", n), ",")
+}
+
+// stringArgs converts strings into query arguments.
+func stringArgs(values []string) []any {
+	args := make([]any, len(values))
+	for i, v := range values {
+		args[i] = v
+	}
+	return args
+}
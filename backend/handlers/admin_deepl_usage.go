@@ -1,32 +1,48 @@
 package handlers
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"net/http"
-	"os"
+
+	"github.com/frogfromlake/Orbitalone/backend/localization"
 )
 
+// usageResponse is the provider's own usage counter next to our local accounting.
+// The provider fields stay at the top level for existing clients.
+type usageResponse struct {
+	*localization.Usage
+	Error string                     `json:"error,omitempty"`
+	Local *localization.BudgetStatus `json:"local,omitempty"`
+}
+
 // GetDeepLUsage handles GET /admin/deepl/usage requests.
-func GetDeepLUsage(w http.ResponseWriter, r *http.Request) {
-	apiKey := os.Getenv("DEEPL_API_KEY")
-	if apiKey == "" {
-		http.Error(w, "DeepL API key not configured", http.StatusInternalServerError)
-		return
-	}
+// It reports the character usage of the configured translation provider together with
+// the locally tracked budget. If the provider cannot report usage, the status code
+// reflects that while the local accounting is still included.
+func GetDeepLUsage(translator localization.Translator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var response usageResponse
+		if local, ok := localization.Budget(translator); ok {
+			response.Local = &local
+		}
 
-	req, _ := http.NewRequest("GET", "https://api-free.deepl.com/v2/usage", nil)
-	req.Header.Set("Authorization", "DeepL-Auth-Key "+apiKey)
+		status := http.StatusOK
+		usage, err := localization.ProviderUsage(r.Context(), translator)
+		switch {
+		case errors.Is(err, localization.ErrUsageUnsupported):
+			status = http.StatusNotImplemented
+			response.Error = fmt.Sprintf("%s translator does not report usage", translator.Name())
+		case err != nil:
+			status = http.StatusBadGateway
+			response.Error = fmt.Sprintf("Failed to fetch DeepL usage: %v", err)
+		default:
+			response.Usage = &usage
+		}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		http.Error(w, fmt.Sprintf("Failed to fetch DeepL usage: %v", err), http.StatusBadGateway)
-		return
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(response)
 	}
-	defer resp.Body.Close()
-
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(resp.StatusCode)
-	io.Copy(w, resp.Body) // directly proxy DeepL JSON body
 }
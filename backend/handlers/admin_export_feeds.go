@@ -2,14 +2,25 @@ package handlers
 
 import (
 	"encoding/json"
+	"log"
 	"net/http"
 
 	"github.com/frogfromlake/Orbitalone/backend/feeds"
 )
 
 // AdminExportFeedsHandler returns all configured feeds as JSON backup.
+// The output is an array of FeedConfig objects that can be fed back into the import endpoint.
 func AdminExportFeedsHandler(w http.ResponseWriter, r *http.Request) {
-	data := feeds.ListAllFeeds()
+	data, err := feeds.ListFeedConfigs()
+	if err != nil {
+		log.Printf("❌ Failed to export feeds: %v", err)
+		http.Error(w, "Failed to export feeds", http.StatusInternalServerError)
+		return
+	}
+	if data == nil {
+		data = []feeds.FeedConfig{}
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(data)
 }
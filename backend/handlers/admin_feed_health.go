@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/frogfromlake/Orbitalone/backend/feeds"
+	"github.com/frogfromlake/Orbitalone/backend/middleware"
+)
+
+// feedHealthEntry is a feed's health state together with its recent error history.
+type feedHealthEntry struct {
+	feeds.FeedState
+	Errors []feeds.FeedError `json:"errors"`
+}
+
+// AdminFeedHealthHandler lists feeds whose recent fetches failed, with their error history.
+// Pass ?all=true to include healthy feeds as well.
+func AdminFeedHealthHandler(w http.ResponseWriter, r *http.Request) {
+	middleware.SetCORSHeaders(w, r)
+
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	states, err := feeds.ListUnhealthyFeeds(r.URL.Query().Get("all") == "true")
+	if err != nil {
+		log.Printf("❌ Failed to list feed health: %v", err)
+		http.Error(w, "Failed to list feed health", http.StatusInternalServerError)
+		return
+	}
+
+	response := make([]feedHealthEntry, 0, len(states))
+	for _, state := range states {
+		history, err := feeds.GetFeedErrors(state.URL)
+		if err != nil {
+			log.Printf("⚠️ Failed to load error history for %s: %v", state.URL, err)
+		}
+		if history == nil {
+			history = []feeds.FeedError{}
+		}
+		response = append(response, feedHealthEntry{FeedState: state, Errors: history})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("❌ Failed to encode feed health: %v", err)
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
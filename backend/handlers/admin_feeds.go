@@ -28,14 +28,14 @@ func AdminFeedsHandler(w http.ResponseWriter, r *http.Request) {
 
 // handleListFeeds responds with the full list of country feed configurations in JSON format.
 func handleListFeeds(w http.ResponseWriter) {
-	allFeeds := feeds.ListAllFeeds()
-
-	response := make([]feeds.FeedConfig, 0, len(allFeeds))
-	for country, urls := range allFeeds {
-		response = append(response, feeds.FeedConfig{
-			CountryCode: country,
-			Feeds:       urls,
-		})
+	response, err := feeds.ListFeedConfigs()
+	if err != nil {
+		log.Printf("❌ Failed to list feeds: %v", err)
+		http.Error(w, "Failed to list feeds", http.StatusInternalServerError)
+		return
+	}
+	if response == nil {
+		response = []feeds.FeedConfig{}
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -46,6 +46,7 @@ func handleListFeeds(w http.ResponseWriter) {
 }
 
 // handleSetFeeds decodes a FeedConfig from the request body and saves it to the feeds store.
+// Full `sources` entries are preferred over the plain `feeds` URL list when both are sent.
 // Returns a confirmation payload on success or an error on failure.
 func handleSetFeeds(w http.ResponseWriter, r *http.Request) {
 	var payload feeds.FeedConfig
@@ -56,29 +57,54 @@ func handleSetFeeds(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if payload.CountryCode == "" || len(payload.Feeds) == 0 {
+	if payload.CountryCode == "" || (len(payload.Feeds) == 0 && len(payload.Sources) == 0) {
 		log.Println("❌ Missing country code or feed list in payload")
 		http.Error(w, "Missing country or feeds", http.StatusBadRequest)
 		return
 	}
 
-	if err := feeds.SetFeeds(payload.CountryCode, payload.Feeds); err != nil {
+	if err := saveFeedConfig(payload, adminUser(r)); err != nil {
 		log.Printf("❌ Failed to save feeds for %s: %v", payload.CountryCode, err)
 		http.Error(w, "Failed to save feeds", http.StatusInternalServerError)
 		return
 	}
 
+	sources, err := feeds.GetFeedSources(payload.CountryCode)
+	if err != nil {
+		log.Printf("⚠️ Failed to reload feeds for %s: %v", payload.CountryCode, err)
+	}
+	urls := make([]string, 0, len(sources))
+	for _, s := range sources {
+		urls = append(urls, s.URL)
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(map[string]any{
 		"status":  "ok",
 		"country": payload.CountryCode,
-		"feeds":   payload.Feeds,
+		"feeds":   urls,
+		"sources": sources,
 	}); err != nil {
 		log.Printf("❌ Failed to encode success response: %v", err)
 		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
 	}
 }
 
+// saveFeedConfig stores a FeedConfig, using its full sources when provided
+// and falling back to the plain URL list otherwise.
+func saveFeedConfig(cfg feeds.FeedConfig, addedBy string) error {
+	if len(cfg.Sources) > 0 {
+		return feeds.SetFeedSources(cfg.CountryCode, cfg.Sources, addedBy)
+	}
+	return feeds.SetFeeds(cfg.CountryCode, cfg.Feeds, addedBy)
+}
+
+// adminUser returns the Basic Auth username of the request, if any.
+func adminUser(r *http.Request) string {
+	user, _, _ := r.BasicAuth()
+	return user
+}
+
 // handleDeleteFeeds deletes the feeds for a given country.
 // It responds with a confirmation payload or an error.
 func handleDeleteFeeds(w http.ResponseWriter, r *http.Request) {
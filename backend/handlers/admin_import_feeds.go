@@ -10,7 +10,8 @@ import (
 )
 
 // AdminImportFeedsHandler handles importing a batch of feeds into the database.
-// It expects a JSON array of FeedConfig objects and requires admin authentication.
+// It expects a JSON array of FeedConfig objects (as produced by the export endpoint)
+// and requires admin authentication.
 func AdminImportFeedsHandler(w http.ResponseWriter, r *http.Request) {
 	middleware.SetCORSHeaders(w, r)
 
@@ -28,11 +29,11 @@ func AdminImportFeedsHandler(w http.ResponseWriter, r *http.Request) {
 
 	imported := 0
 	for _, cfg := range input {
-		if cfg.CountryCode == "" || len(cfg.Feeds) == 0 {
+		if cfg.CountryCode == "" || (len(cfg.Feeds) == 0 && len(cfg.Sources) == 0) {
 			log.Printf("⚠️ Skipped invalid entry: %+v\n", cfg)
 			continue
 		}
-		if err := feeds.SetFeeds(cfg.CountryCode, cfg.Feeds); err != nil {
+		if err := saveFeedConfig(cfg, adminUser(r)); err != nil {
 			log.Printf("⚠️ Failed to save feeds for %s: %v\n", cfg.CountryCode, err)
 			continue
 		}
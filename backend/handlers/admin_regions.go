@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/frogfromlake/Orbitalone/backend/feeds"
+	"github.com/frogfromlake/Orbitalone/backend/middleware"
+)
+
+// AdminRegionsHandler manages the region registry behind /api/news?region= and ?continent=.
+//
+//	GET    /admin/regions
+//	POST   /admin/regions   {"kind": "region", "code": "EU", "name": "European Union", "countries": ["AT", ...]}
+//	DELETE /admin/regions?kind=region&code=EU
+func AdminRegionsHandler(w http.ResponseWriter, r *http.Request) {
+	middleware.SetCORSHeaders(w, r)
+
+	switch r.Method {
+	case http.MethodGet:
+		regions, err := feeds.ListRegions()
+		if err != nil {
+			log.Printf("❌ Failed to list regions: %v", err)
+			http.Error(w, "Failed to list regions", http.StatusInternalServerError)
+			return
+		}
+		if regions == nil {
+			regions = []feeds.Region{}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(regions); err != nil {
+			log.Printf("❌ Failed to encode regions: %v", err)
+		}
+
+	case http.MethodPost:
+		var region feeds.Region
+		if err := json.NewDecoder(r.Body).Decode(&region); err != nil {
+			http.Error(w, "Invalid JSON", http.StatusBadRequest)
+			return
+		}
+		if region.Kind == "" {
+			region.Kind = feeds.RegionKindRegion
+		}
+		if region.Kind != feeds.RegionKindRegion && region.Kind != feeds.RegionKindContinent {
+			http.Error(w, "Invalid kind (region or continent)", http.StatusBadRequest)
+			return
+		}
+		if region.Code == "" || len(region.Countries) == 0 {
+			http.Error(w, "Missing code or countries", http.StatusBadRequest)
+			return
+		}
+		if err := feeds.SaveRegion(region); err != nil {
+			log.Printf("❌ Failed to save region %s: %v", region.Code, err)
+			http.Error(w, "Failed to save region", http.StatusInternalServerError)
+			return
+		}
+
+		saved, err := feeds.GetRegion(region.Kind, region.Code)
+		if err != nil {
+			log.Printf("⚠️ Failed to reload region %s: %v", region.Code, err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(saved); err != nil {
+			log.Printf("❌ Failed to encode region: %v", err)
+		}
+
+	case http.MethodDelete:
+		kind, code := r.URL.Query().Get("kind"), r.URL.Query().Get("code")
+		if kind == "" {
+			kind = feeds.RegionKindRegion
+		}
+		if code == "" {
+			http.Error(w, "Missing region code", http.StatusBadRequest)
+			return
+		}
+		err := feeds.DeleteRegion(kind, code)
+		if errors.Is(err, feeds.ErrUnknownRegion) {
+			http.Error(w, "Unknown region", http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			log.Printf("❌ Failed to delete region %s: %v", code, err)
+			http.Error(w, "Failed to delete region", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
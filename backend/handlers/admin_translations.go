@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/frogfromlake/Orbitalone/backend/feeds"
+	"github.com/frogfromlake/Orbitalone/backend/localization"
+	"github.com/frogfromlake/Orbitalone/backend/middleware"
+	"github.com/frogfromlake/Orbitalone/backend/utils"
+)
+
+// maxTranslationsPage bounds how many cached translations are listed per request.
+const maxTranslationsPage = 200
+
+// translationsResponse is a page of the persistent translation cache.
+type translationsResponse struct {
+	Total   int                 `json:"total"`
+	Entries []feeds.Translation `json:"entries"`
+}
+
+// AdminTranslationsHandler inspects and purges the persistent translation cache.
+//
+//	GET    /admin/translations?source=DE&target=EN&q=text&limit=50&offset=0
+//	DELETE /admin/translations?key=...|source=DE|target=EN|unusedFor=720h|all=true
+//
+// Purging also empties the in-memory translation caches.
+func AdminTranslationsHandler(translator localization.Translator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		middleware.SetCORSHeaders(w, r)
+
+		query := r.URL.Query()
+		filter := feeds.TranslationFilter{
+			Key:        query.Get("key"),
+			SourceLang: query.Get("source"),
+			TargetLang: query.Get("target"),
+			Query:      query.Get("q"),
+		}
+		if unusedFor := query.Get("unusedFor"); unusedFor != "" {
+			d, err := time.ParseDuration(unusedFor)
+			if err != nil || d <= 0 {
+				http.Error(w, "Invalid 'unusedFor' duration", http.StatusBadRequest)
+				return
+			}
+			filter.UnusedBefore = time.Now().Add(-d)
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			limit := queryInt(r, "limit", 50)
+			if limit <= 0 || limit > maxTranslationsPage {
+				limit = maxTranslationsPage
+			}
+			entries, total, err := feeds.ListTranslations(filter, limit, queryInt(r, "offset", 0))
+			if err != nil {
+				log.Printf("❌ Failed to list translations: %v", err)
+				http.Error(w, "Failed to list translations", http.StatusInternalServerError)
+				return
+			}
+			if entries == nil {
+				entries = []feeds.Translation{}
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			if err := json.NewEncoder(w).Encode(translationsResponse{Total: total, Entries: entries}); err != nil {
+				log.Printf("❌ Failed to encode translations: %v", err)
+			}
+
+		case http.MethodDelete:
+			var (
+				purged int64
+				err    error
+			)
+			if query.Get("all") == "true" {
+				purged, err = feeds.PurgeAllTranslations()
+			} else {
+				purged, err = feeds.PurgeTranslations(filter)
+			}
+			if errors.Is(err, feeds.ErrEmptyFilter) {
+				http.Error(w, "Specify a filter or all=true", http.StatusBadRequest)
+				return
+			}
+			if err != nil {
+				log.Printf("❌ Failed to purge translations: %v", err)
+				http.Error(w, "Failed to purge translations", http.StatusInternalServerError)
+				return
+			}
+
+			localization.FlushCaches(translator)
+			utils.ClearTranslatedArticles()
+			log.Printf("🧹 Purged %d cached translations", purged)
+
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]int64{"purged": purged})
+
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	}
+}
+
+// queryInt parses an integer query parameter, returning def when it is absent or invalid.
+func queryInt(r *http.Request, name string, def int) int {
+	v, err := strconv.Atoi(r.URL.Query().Get(name))
+	if err != nil {
+		return def
+	}
+	return v
+}
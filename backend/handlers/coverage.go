@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/frogfromlake/Orbitalone/backend/middleware"
+	"github.com/frogfromlake/Orbitalone/backend/utils"
+)
+
+// CoverageHandler handles GET /api/coverage requests. It reports, per ISO country code,
+// how many feeds a country has, how many of them are healthy, how many articles they
+// published in the last 24 hours, when they were last updated and whether translation is
+// available, so the globe can shade countries by news availability.
+func CoverageHandler(w http.ResponseWriter, r *http.Request) {
+	middleware.SetCORSHeaders(w, r)
+
+	// Handle CORS preflight request
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	coverage, err := utils.GetCoverage()
+	if err != nil {
+		log.Printf("❌ Failed to load coverage: %v", err)
+		http.Error(w, "Failed to load coverage", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	if err := json.NewEncoder(w).Encode(coverage); err != nil {
+		log.Printf("❌ Failed to encode coverage: %v", err)
+	}
+}
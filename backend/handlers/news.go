@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/frogfromlake/Orbitalone/backend/feeds"
+	"github.com/frogfromlake/Orbitalone/backend/localization"
 	"github.com/frogfromlake/Orbitalone/backend/middleware"
 	"github.com/frogfromlake/Orbitalone/backend/utils"
 )
@@ -15,55 +16,123 @@ import (
 // NewsHandler handles GET requests for country-specific news articles.
 // It expects a `country` query parameter (ISO Alpha-2 code) and returns a list of RSS articles in JSON format.
 // If no feeds or articles are found, it returns 204 No Content.
-func NewsHandler(w http.ResponseWriter, r *http.Request) {
-	middleware.SetCORSHeaders(w, r)
+// With `translate=true` (or an explicit `lang`) the articles are translated by the given translator
+// into the `lang` query parameter, falling back to Accept-Language and then English.
+// Translations missing from the cache are queued on the worker and the originals are returned with
+// `translationStatus: "pending"`; `wait=true` (or a nil worker) blocks until they are translated.
+// `countries`, `region` and `continent` query several countries at once (see serveMultiCountryNews),
+// and `limit`, `cursor` and `since` page through a country's news (see serveNewsPage).
+// `ocean` (an ocean region ID from 10000 up) serves the maritime news of an ocean region in place of a country.
+func NewsHandler(translator localization.Translator, worker *utils.TranslationWorker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		middleware.SetCORSHeaders(w, r)
 
-	// Handle CORS preflight request
-	if r.Method == http.MethodOptions {
-		w.WriteHeader(http.StatusNoContent)
-		return
-	}
+		// Handle CORS preflight request
+		if r.Method == http.MethodOptions {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
 
-	// Extract and validate the 'country' query parameter
-	countryCode := strings.ToUpper(r.URL.Query().Get("country"))
-	if countryCode == "" {
-		http.Error(w, "Missing 'country' query parameter", http.StatusBadRequest)
-		return
-	}
+		// Translation settings apply to single- and multi-country queries alike
+		var opts utils.NewsOptions
+		if r.URL.Query().Get("translate") == "true" || r.URL.Query().Get("lang") != "" {
+			targetLang, ok := requestTargetLang(r)
+			if !ok {
+				http.Error(w, "Unsupported 'lang' query parameter", http.StatusBadRequest)
+				return
+			}
+			opts = utils.NewsOptions{Translator: translator, TargetLang: targetLang}
+			if r.URL.Query().Get("wait") != "true" {
+				opts.Worker = worker
+			}
+		}
+
+		// Several countries, a region or a continent get a per-country report
+		query := r.URL.Query()
+		if query.Has("countries") || query.Has("region") || query.Has("continent") {
+			serveMultiCountryNews(w, r, opts)
+			return
+		}
 
-	// Retrieve news articles for the specified country
-	translateParam := r.URL.Query().Get("translate")
-	shouldTranslate := translateParam == "true"
+		// Extract and validate the 'country' (or 'ocean') query parameter
+		countryCode := strings.ToUpper(r.URL.Query().Get("country"))
+		if query.Has("ocean") {
+			code, err := utils.OceanChannel(query.Get("ocean"))
+			if err != nil {
+				http.Error(w, "Invalid 'ocean' query parameter", http.StatusBadRequest)
+				return
+			}
+			countryCode = code
+		}
+		if countryCode == "" {
+			http.Error(w, "Missing 'country' query parameter", http.StatusBadRequest)
+			return
+		}
 
-	articles, err := utils.GetNewsByCountry(countryCode, shouldTranslate)
-	if err != nil {
-		// Specific case: No feeds available for this country
-		if errors.Is(err, feeds.ErrNoFeeds) {
-			log.Printf("⚠️  No feeds for country: %s\n", countryCode)
+		// limit, cursor and since switch to a paginated response
+		if query.Has("limit") || query.Has("cursor") || query.Has("since") {
+			serveNewsPage(w, r, countryCode, opts)
+			return
+		}
+
+		// Retrieve news articles for the specified country
+		articles, err := utils.GetNewsByCountry(countryCode, opts)
+		if err != nil {
+			// Specific case: No feeds available for this country
+			if errors.Is(err, feeds.ErrNoFeeds) {
+				log.Printf("⚠️  No feeds for country: %s\n", countryCode)
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+
+			// Generic failure
+			log.Printf("❌ Failed to fetch news for %s: %v\n", countryCode, err)
+			http.Error(w, "Failed to fetch news", http.StatusInternalServerError)
+			return
+		}
+
+		// No articles found, respond with 204 No Content
+		if len(articles) == 0 {
+			log.Printf("ℹ️  No articles returned for %s\n", countryCode)
 			w.WriteHeader(http.StatusNoContent)
 			return
 		}
 
-		// Generic failure
-		log.Printf("❌ Failed to fetch news for %s: %v\n", countryCode, err)
-		http.Error(w, "Failed to fetch news", http.StatusInternalServerError)
-		return
-	}
+		// Set headers for caching and response type
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "public, max-age=60")
+		if hasPendingTranslations(articles) {
+			// Clients re-poll for finished translations, so don't let caches pin the originals
+			w.Header().Set("Cache-Control", "no-store")
+		}
+		w.Header().Set("Vary", "Accept-Language")
 
-	// No articles found, respond with 204 No Content
-	if len(articles) == 0 {
-		log.Printf("ℹ️  No articles returned for %s\n", countryCode)
-		w.WriteHeader(http.StatusNoContent)
-		return
+		// Encode and send article list
+		if err := json.NewEncoder(w).Encode(articles); err != nil {
+			log.Printf("❌ Failed to encode response for %s: %v\n", countryCode, err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+		}
 	}
+}
 
-	// Set headers for caching and response type
-	w.Header().Set("Content-Type", "application/json")
-	w.Header().Set("Cache-Control", "public, max-age=60")
+// requestTargetLang returns the translation target of a request: the `lang` query parameter,
+// falling back to Accept-Language and then English. It reports false for an unsupported `lang`.
+func requestTargetLang(r *http.Request) (string, bool) {
+	if langParam := r.URL.Query().Get("lang"); langParam != "" {
+		return localization.NormalizeTargetLang(langParam)
+	}
+	if lang, ok := localization.TargetFromAcceptLanguage(r.Header.Get("Accept-Language")); ok {
+		return lang, true
+	}
+	return localization.DefaultTargetLang, true
+}
 
-	// Encode and send article list
-	if err := json.NewEncoder(w).Encode(articles); err != nil {
-		log.Printf("❌ Failed to encode response for %s: %v\n", countryCode, err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+// hasPendingTranslations reports whether any article is still being translated.
+func hasPendingTranslations(articles []utils.NewsArticle) bool {
+	for _, a := range articles {
+		if a.TranslationStatus == utils.TranslationPending {
+			return true
+		}
 	}
+	return false
 }
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/frogfromlake/Orbitalone/backend/feeds"
+	"github.com/frogfromlake/Orbitalone/backend/utils"
+)
+
+// maxNewsCountries bounds how many countries a single /api/news request may cover.
+const maxNewsCountries = 60
+
+// multiNewsResponse reports every requested country. With group=merged the articles of
+// all countries are listed together in Articles instead of per country.
+type multiNewsResponse struct {
+	Countries []utils.CountryNews `json:"countries"`
+	Articles  []utils.NewsArticle `json:"articles,omitempty"`
+}
+
+// serveMultiCountryNews answers /api/news queries for several countries:
+//
+//	GET /api/news?countries=DE,FR,PL
+//	GET /api/news?region=EU
+//	GET /api/news?continent=AF&group=merged
+//
+// The parameters can be combined; their countries are joined in order without duplicates.
+// Every country is reported with a status (ok, empty, no_feeds or error), so one failing
+// country does not fail the whole request.
+func serveMultiCountryNews(w http.ResponseWriter, r *http.Request, opts utils.NewsOptions) {
+	codes, err := requestedCountries(r)
+	switch {
+	case errors.Is(err, feeds.ErrUnknownRegion):
+		http.Error(w, "Unknown 'region' or 'continent'", http.StatusBadRequest)
+		return
+	case err != nil:
+		log.Printf("❌ Failed to resolve region: %v", err)
+		http.Error(w, "Failed to resolve region", http.StatusInternalServerError)
+		return
+	case len(codes) == 0:
+		http.Error(w, "No countries requested", http.StatusBadRequest)
+		return
+	case len(codes) > maxNewsCountries:
+		http.Error(w, "Too many countries in one request", http.StatusBadRequest)
+		return
+	}
+
+	group := r.URL.Query().Get("group")
+	if group != "" && group != "country" && group != "merged" {
+		http.Error(w, "Invalid 'group' query parameter (country or merged)", http.StatusBadRequest)
+		return
+	}
+
+	response := multiNewsResponse{Countries: utils.GetNewsByCountries(r.Context(), codes, opts)}
+	if r.Context().Err() != nil {
+		// The client is gone; nobody reads the answer
+		return
+	}
+
+	pending := false
+	for _, c := range response.Countries {
+		pending = pending || hasPendingTranslations(c.Articles)
+	}
+	if group == "merged" {
+		response.Articles = utils.MergeCountryNews(response.Countries)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=60")
+	if pending {
+		w.Header().Set("Cache-Control", "no-store")
+	}
+	w.Header().Set("Vary", "Accept-Language")
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("❌ Failed to encode multi-country response: %v", err)
+	}
+}
+
+// requestedCountries collects the country codes of the countries, region and continent
+// query parameters, in that order and without duplicates.
+func requestedCountries(r *http.Request) ([]string, error) {
+	query := r.URL.Query()
+
+	var (
+		codes []string
+		seen  = make(map[string]bool)
+	)
+	add := func(list []string) {
+		for _, code := range list {
+			code = strings.ToUpper(strings.TrimSpace(code))
+			if code != "" && !seen[code] {
+				seen[code] = true
+				codes = append(codes, code)
+			}
+		}
+	}
+
+	add(strings.Split(query.Get("countries"), ","))
+	for _, kind := range []string{feeds.RegionKindRegion, feeds.RegionKindContinent} {
+		if code := query.Get(kind); code != "" {
+			region, err := feeds.GetRegion(kind, code)
+			if err != nil {
+				return nil, err
+			}
+			add(region.Countries)
+		}
+	}
+	return codes, nil
+}
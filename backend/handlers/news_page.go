@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/frogfromlake/Orbitalone/backend/feeds"
+	"github.com/frogfromlake/Orbitalone/backend/utils"
+)
+
+// serveNewsPage answers paginated /api/news queries for a single country:
+//
+//	GET /api/news?country=DE&limit=20
+//	GET /api/news?country=DE&limit=20&cursor=<nextCursor>
+//	GET /api/news?country=DE&since=2025-04-25T00:00:00Z
+//
+// It responds with {"articles", "nextCursor", "total"}; nextCursor is omitted on the last page.
+// Limits above utils.MaxPageLimit are clamped.
+func serveNewsPage(w http.ResponseWriter, r *http.Request, countryCode string, opts utils.NewsOptions) {
+	query := r.URL.Query()
+	page := utils.PageOptions{Limit: utils.DefaultPageLimit, Cursor: query.Get("cursor")}
+
+	if raw := query.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 {
+			http.Error(w, "Invalid 'limit' query parameter", http.StatusBadRequest)
+			return
+		}
+		page.Limit = min(limit, utils.MaxPageLimit)
+	}
+	if raw := query.Get("since"); raw != "" {
+		since, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			http.Error(w, "Invalid 'since' query parameter (RFC3339 expected)", http.StatusBadRequest)
+			return
+		}
+		page.Since = &since
+	}
+
+	result, err := utils.GetNewsPage(countryCode, page, opts)
+	switch {
+	case errors.Is(err, utils.ErrInvalidCursor):
+		http.Error(w, "Invalid 'cursor' query parameter", http.StatusBadRequest)
+		return
+	case errors.Is(err, feeds.ErrNoFeeds):
+		log.Printf("⚠️  No feeds for country: %s\n", countryCode)
+		w.WriteHeader(http.StatusNoContent)
+		return
+	case err != nil:
+		log.Printf("❌ Failed to fetch news page for %s: %v\n", countryCode, err)
+		http.Error(w, "Failed to fetch news", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=60")
+	if hasPendingTranslations(result.Articles) {
+		w.Header().Set("Cache-Control", "no-store")
+	}
+	w.Header().Set("Vary", "Accept-Language")
+
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		log.Printf("❌ Failed to encode news page for %s: %v\n", countryCode, err)
+	}
+}
@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequestTargetLang(t *testing.T) {
+	tests := []struct {
+		query, acceptLanguage string
+		want                  string
+		ok                    bool
+	}{
+		{"", "", "EN", true},
+		{"", "fr-CH, fr;q=0.9", "FR", true},
+		{"lang=pt-br", "fr", "PT-BR", true}, // the parameter wins over the header
+		{"lang=xx", "fr", "", false},        // an unsupported parameter is an error, not a fallback
+		{"", "tlh", "EN", true},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", "/api/news?country=DE&"+tt.query, nil)
+		if tt.acceptLanguage != "" {
+			r.Header.Set("Accept-Language", tt.acceptLanguage)
+		}
+		got, ok := requestTargetLang(r)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("%q with Accept-Language %q: %q, %v; want %q, %v", tt.query, tt.acceptLanguage, got, ok, tt.want, tt.ok)
+		}
+	}
+}
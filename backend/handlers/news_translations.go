@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/frogfromlake/Orbitalone/backend/middleware"
+	"github.com/frogfromlake/Orbitalone/backend/utils"
+)
+
+// maxStatusIDs bounds how many article IDs one status request may ask for.
+const maxStatusIDs = 100
+
+// NewsTranslationsHandler handles GET /api/news/translations?ids=a,b,c&lang=DE.
+// It reports the translation state of articles returned as pending by /api/news, including
+// the translated fields of finished articles. The target language is resolved like in
+// NewsHandler. Articles the worker knows nothing about are left out of the response.
+func NewsTranslationsHandler(worker *utils.TranslationWorker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		middleware.SetCORSHeaders(w, r)
+
+		// Handle CORS preflight request
+		if r.Method == http.MethodOptions {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var ids []string
+		for _, id := range strings.Split(r.URL.Query().Get("ids"), ",") {
+			if id = strings.TrimSpace(id); id != "" {
+				ids = append(ids, id)
+			}
+		}
+		if len(ids) == 0 {
+			http.Error(w, "Missing 'ids' query parameter", http.StatusBadRequest)
+			return
+		}
+		if len(ids) > maxStatusIDs {
+			http.Error(w, "Too many article IDs", http.StatusBadRequest)
+			return
+		}
+
+		targetLang, ok := requestTargetLang(r)
+		if !ok {
+			http.Error(w, "Unsupported 'lang' query parameter", http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		if err := json.NewEncoder(w).Encode(worker.Status(ids, targetLang)); err != nil {
+			log.Printf("❌ Failed to encode translation status: %v", err)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/frogfromlake/Orbitalone/backend/localization"
+	"github.com/frogfromlake/Orbitalone/backend/middleware"
+	"github.com/frogfromlake/Orbitalone/backend/utils"
+)
+
+// Bounds of a search request.
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 50
+	maxSearchOffset    = 1000
+)
+
+// SearchNewsHandler handles GET /api/search/news requests:
+//
+//	GET /api/search/news?q=wildfire
+//	GET /api/search/news?q=wildfire&countries=GR,TR&from=2025-07-01&to=2025-08-01&lang=en&limit=20&offset=0
+//
+// It searches the original and translated titles and descriptions of all ingested
+// articles. `lang` restricts matches to text in that language; `from` and `to` accept
+// dates or RFC3339 timestamps. Limits above maxSearchLimit are clamped.
+func SearchNewsHandler(w http.ResponseWriter, r *http.Request) {
+	middleware.SetCORSHeaders(w, r)
+
+	// Handle CORS preflight request
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	opts := utils.SearchOptions{
+		Query:  strings.TrimSpace(query.Get("q")),
+		Limit:  defaultSearchLimit,
+		Offset: queryInt(r, "offset", 0),
+	}
+	if opts.Query == "" {
+		http.Error(w, "Missing 'q' query parameter", http.StatusBadRequest)
+		return
+	}
+	if raw := query.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 {
+			http.Error(w, "Invalid 'limit' query parameter", http.StatusBadRequest)
+			return
+		}
+		opts.Limit = min(limit, maxSearchLimit)
+	}
+	if opts.Offset < 0 || opts.Offset > maxSearchOffset {
+		http.Error(w, "Invalid 'offset' query parameter", http.StatusBadRequest)
+		return
+	}
+
+	for _, code := range strings.Split(query.Get("countries")+","+query.Get("country"), ",") {
+		if code = strings.ToUpper(strings.TrimSpace(code)); code != "" {
+			opts.Countries = append(opts.Countries, code)
+		}
+	}
+	if lang := query.Get("lang"); lang != "" {
+		if opts.Language = localization.NormalizeLanguage(lang); opts.Language == "" {
+			http.Error(w, "Invalid 'lang' query parameter", http.StatusBadRequest)
+			return
+		}
+	}
+
+	var ok bool
+	if opts.From, ok = parseSearchTime(query.Get("from")); !ok {
+		http.Error(w, "Invalid 'from' query parameter", http.StatusBadRequest)
+		return
+	}
+	if opts.To, ok = parseSearchTime(query.Get("to")); !ok {
+		http.Error(w, "Invalid 'to' query parameter", http.StatusBadRequest)
+		return
+	}
+
+	result, err := utils.SearchNews(opts)
+	if errors.Is(err, utils.ErrEmptyQuery) {
+		http.Error(w, "Search query has no searchable words", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		log.Printf("❌ Search for %q failed: %v", opts.Query, err)
+		http.Error(w, "Search failed", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=60")
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		log.Printf("❌ Failed to encode search results: %v", err)
+	}
+}
+
+// parseSearchTime parses a date (2006-01-02, UTC) or RFC3339 timestamp. An empty value
+// means no bound.
+func parseSearchTime(value string) (*time.Time, bool) {
+	if value == "" {
+		return nil, true
+	}
+	for _, layout := range []string{time.RFC3339, time.DateOnly} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return &t, true
+		}
+	}
+	return nil, false
+}
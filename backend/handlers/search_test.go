@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSearchNewsHandlerRejectsInvalidParameters(t *testing.T) {
+	for _, query := range []string{
+		"",
+		"q=fire&limit=0",
+		"q=fire&limit=-5",
+		"q=fire&limit=ten",
+		"q=fire&offset=-1",
+		"q=fire&offset=5000",
+		"q=fire&to=2025-13-01",
+		"q=fire&from=yesterday",
+	} {
+		rec := httptest.NewRecorder()
+		SearchNewsHandler(rec, httptest.NewRequest(http.MethodGet, "/api/search/news?"+query, nil))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%q: status %d, want 400", query, rec.Code)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"math"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"github.com/frogfromlake/Orbitalone/backend/localization"
+	"github.com/frogfromlake/Orbitalone/backend/middleware"
+	"github.com/frogfromlake/Orbitalone/backend/utils"
+)
+
+// Limits of a single on-demand translation request.
+const (
+	maxTranslateEntries = 20        // article IDs and text items together
+	maxTranslateBody    = 256 << 10 // bytes
+)
+
+// defaultTextCharsPerHour is how many characters of free-text items a client IP may
+// translate per hour unless TRANSLATE_TEXT_CHARS_PER_HOUR says otherwise.
+const defaultTextCharsPerHour = 20000
+
+// translateRequest selects what POST /api/translate translates.
+type translateRequest struct {
+	Lang  string           `json:"lang,omitempty"`
+	IDs   []string         `json:"ids,omitempty"`
+	Items []utils.TextItem `json:"items,omitempty"`
+}
+
+// translateResponse holds the translations in request order.
+type translateResponse struct {
+	TargetLang string                     `json:"targetLang"`
+	Articles   []utils.ArticleTranslation `json:"articles"`
+	Items      []utils.ArticleTranslation `json:"items"`
+	Missing    []string                   `json:"missing,omitempty"` // IDs of unknown articles
+}
+
+// TranslateHandler handles POST /api/translate requests, translating just the given
+// articles instead of a whole country's news:
+//
+//	{"lang": "de", "ids": ["85f9..."], "items": [{"title": "...", "description": "...", "country": "FR"}]}
+//
+// The target language comes from `lang` in the body, then the `lang` query parameter,
+// Accept-Language and English. Translations share the caches and budget with /api/news.
+// Free-text items are limited per client IP, so the endpoint can't serve as an open
+// translation proxy; stored articles are not.
+func TranslateHandler(translator localization.Translator) http.HandlerFunc {
+	textLimiter := middleware.NewRateLimiter(textCharsPerHourFromEnv(), time.Hour)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		middleware.SetCORSHeaders(w, r)
+
+		// Handle CORS preflight request
+		if r.Method == http.MethodOptions {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var req translateRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxTranslateBody)).Decode(&req); err != nil {
+			http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+			return
+		}
+		if len(req.IDs)+len(req.Items) == 0 {
+			http.Error(w, "Nothing to translate: pass 'ids' or 'items'", http.StatusBadRequest)
+			return
+		}
+		if len(req.IDs)+len(req.Items) > maxTranslateEntries {
+			http.Error(w, "Too many articles in one request", http.StatusBadRequest)
+			return
+		}
+
+		targetLang, ok := requestTargetLang(r)
+		if req.Lang != "" {
+			targetLang, ok = localization.NormalizeTargetLang(req.Lang)
+		}
+		if !ok {
+			http.Error(w, "Unsupported 'lang'", http.StatusBadRequest)
+			return
+		}
+
+		if len(req.Items) > 0 {
+			chars := 0
+			for _, item := range req.Items {
+				chars += utf8.RuneCountInString(item.Title) + utf8.RuneCountInString(item.Description)
+			}
+			if ok, wait := textLimiter.Allow(r, chars); !ok {
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+				http.Error(w, "Text translation limit reached, try again later", http.StatusTooManyRequests)
+				return
+			}
+		}
+
+		articles, missing, err := utils.TranslateArticlesByID(r.Context(), translator, req.IDs, targetLang)
+		if err != nil {
+			log.Printf("❌ Failed to translate articles: %v", err)
+			http.Error(w, "Failed to translate articles", http.StatusInternalServerError)
+			return
+		}
+
+		response := translateResponse{
+			TargetLang: targetLang,
+			Articles:   articles,
+			Items:      utils.TranslateTextItems(r.Context(), translator, req.Items, targetLang),
+			Missing:    missing,
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			log.Printf("❌ Failed to encode translations: %v", err)
+		}
+	}
+}
+
+// textCharsPerHourFromEnv reads the per-IP hourly character allowance for free-text items
+// from TRANSLATE_TEXT_CHARS_PER_HOUR; 0 disables the limit.
+func textCharsPerHourFromEnv() int {
+	raw := os.Getenv("TRANSLATE_TEXT_CHARS_PER_HOUR")
+	if raw == "" {
+		return defaultTextCharsPerHour
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 0 {
+		log.Printf("⚠️  Invalid TRANSLATE_TEXT_CHARS_PER_HOUR %q, using %d", raw, defaultTextCharsPerHour)
+		return defaultTextCharsPerHour
+	}
+	return n
+}
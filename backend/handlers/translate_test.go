@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/frogfromlake/Orbitalone/backend/localization"
+)
+
+func TestTranslateHandlerRejects(t *testing.T) {
+	t.Setenv("TRANSLATE_TEXT_CHARS_PER_HOUR", "10")
+	handler := TranslateHandler(localization.Echo{})
+
+	tests := []struct {
+		name       string
+		method     string
+		body       string
+		wantStatus int
+	}{
+		{"GET", http.MethodGet, "", http.StatusMethodNotAllowed},
+		{"invalid JSON", http.MethodPost, `{"ids": `, http.StatusBadRequest},
+		{"nothing to translate", http.MethodPost, `{"lang": "de"}`, http.StatusBadRequest},
+		{"too many entries", http.MethodPost, `{"ids": [` + strings.Repeat(`"x", `, maxTranslateEntries) + `"x"]}`, http.StatusBadRequest},
+		{"unsupported language", http.MethodPost, `{"lang": "tlh", "ids": ["x"]}`, http.StatusBadRequest},
+		{"text over the hourly limit", http.MethodPost, `{"items": [{"title": "Eleven char"}]}`, http.StatusTooManyRequests},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler(rec, httptest.NewRequest(tt.method, "/api/translate", strings.NewReader(tt.body)))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if tt.wantStatus == http.StatusTooManyRequests && rec.Header().Get("Retry-After") != "3600" {
+				t.Errorf("Retry-After = %q, want the rest of the hour", rec.Header().Get("Retry-After"))
+			}
+		})
+	}
+}
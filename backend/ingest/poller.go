@@ -0,0 +1,211 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/frogfromlake/Orbitalone/backend/feeds"
+	"github.com/frogfromlake/Orbitalone/backend/utils"
+)
+
+// Config controls how often feeds are polled and how many are fetched in parallel.
+type Config struct {
+	Interval  time.Duration // pause between two full passes over the feeds table
+	Workers   int           // number of feeds fetched concurrently
+	Retention time.Duration // how long articles are kept; 0 keeps them forever
+}
+
+// ConfigFromEnv reads the poller configuration from FEED_POLL_INTERVAL
+// (a Go duration such as "15m"), FEED_POLL_WORKERS and ARTICLE_RETENTION_DAYS,
+// falling back to defaults.
+func ConfigFromEnv() Config {
+	cfg := Config{
+		Interval:  15 * time.Minute,
+		Workers:   4,
+		Retention: 30 * 24 * time.Hour,
+	}
+
+	if raw := os.Getenv("FEED_POLL_INTERVAL"); raw != "" {
+		if d, err := time.ParseDuration(raw); err == nil && d > 0 {
+			cfg.Interval = d
+		} else {
+			log.Printf("⚠️  Invalid FEED_POLL_INTERVAL %q, using %v", raw, cfg.Interval)
+		}
+	}
+	if raw := os.Getenv("FEED_POLL_WORKERS"); raw != "" {
+		if n, err := strconv.Atoi(raw); err == nil && n > 0 {
+			cfg.Workers = n
+		} else {
+			log.Printf("⚠️  Invalid FEED_POLL_WORKERS %q, using %d", raw, cfg.Workers)
+		}
+	}
+	if raw := os.Getenv("ARTICLE_RETENTION_DAYS"); raw != "" {
+		if n, err := strconv.Atoi(raw); err == nil && n >= 0 {
+			cfg.Retention = time.Duration(n) * 24 * time.Hour
+		} else {
+			log.Printf("⚠️  Invalid ARTICLE_RETENTION_DAYS %q, using %v", raw, cfg.Retention)
+		}
+	}
+	return cfg
+}
+
+// Poller periodically walks every feed in the feeds table and ingests its items,
+// so that API requests only ever read already-fetched articles.
+type Poller struct {
+	cfg       Config
+	extractor *utils.ExtractionWorker
+	lastPrune time.Time
+	cancel    context.CancelFunc
+	done      chan struct{}
+}
+
+// NewPoller creates a poller with the given configuration. Feeds with content extraction
+// hand their articles to the extractor. Call Start to run it.
+func NewPoller(cfg Config, extractor *utils.ExtractionWorker) *Poller {
+	if cfg.Workers < 1 {
+		cfg.Workers = 1
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = 15 * time.Minute
+	}
+	return &Poller{cfg: cfg, extractor: extractor}
+}
+
+// Start launches the polling loop in the background. The first pass runs immediately.
+func (p *Poller) Start(ctx context.Context) {
+	ctx, p.cancel = context.WithCancel(ctx)
+	p.done = make(chan struct{})
+
+	go func() {
+		defer close(p.done)
+		p.run(ctx)
+	}()
+	log.Printf("🛰️  Feed poller started (every %v, %d workers)", p.cfg.Interval, p.cfg.Workers)
+}
+
+// Stop cancels in-flight fetches and blocks until the polling loop has exited.
+func (p *Poller) Stop() {
+	if p.cancel == nil {
+		return
+	}
+	p.cancel()
+	<-p.done
+	log.Println("🛑 Feed poller stopped")
+}
+
+// run executes a pass, then waits for the next tick until the context is cancelled.
+func (p *Poller) run(ctx context.Context) {
+	ticker := time.NewTicker(p.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		p.pollOnce(ctx)
+		p.pruneIfDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// pollOnce fetches every configured feed once, using a fixed pool of workers.
+func (p *Poller) pollOnce(ctx context.Context) {
+	jobs := feedJobs()
+	if len(jobs) == 0 {
+		return
+	}
+
+	start := time.Now()
+	queue := make(chan feedJob)
+
+	var (
+		wg              sync.WaitGroup
+		mu              sync.Mutex
+		failed, skipped int
+	)
+
+	for i := 0; i < p.cfg.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range queue {
+				job.opts.Extractor = p.extractor
+				err := utils.RefreshFeed(ctx, job.url, job.opts)
+				if err == nil {
+					continue
+				}
+				if ctx.Err() != nil {
+					return
+				}
+
+				mu.Lock()
+				if errors.Is(err, utils.ErrFeedBackingOff) {
+					skipped++
+				} else {
+					log.Printf("⚠️ Failed to ingest %s: %v", job.url, err)
+					failed++
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+dispatch:
+	for _, job := range jobs {
+		select {
+		case <-ctx.Done():
+			break dispatch
+		case queue <- job:
+		}
+	}
+	close(queue)
+	wg.Wait()
+
+	if ctx.Err() == nil {
+		log.Printf("📥 Ingested %d/%d feeds in %v (%d failed, %d backing off)",
+			len(jobs)-failed-skipped, len(jobs), time.Since(start), failed, skipped)
+	}
+}
+
+// feedJob is a single distinct feed URL to ingest, with its merged options.
+type feedJob struct {
+	url  string
+	opts utils.RefreshOptions
+}
+
+// feedJobs flattens the enabled feed sources into one job per distinct URL.
+// A URL configured for several countries gets content extraction if any of them asks for it,
+// and the first configured source language.
+func feedJobs() []feedJob {
+	sources, err := feeds.ListAllFeedSources()
+	if err != nil {
+		log.Printf("❌ Failed to list feeds: %v", err)
+		return nil
+	}
+
+	index := make(map[string]int)
+	var jobs []feedJob
+	for _, s := range sources {
+		if !s.Enabled {
+			continue
+		}
+		i, seen := index[s.URL]
+		if !seen {
+			i = len(jobs)
+			index[s.URL] = i
+			jobs = append(jobs, feedJob{url: s.URL})
+		}
+		jobs[i].opts.ExtractContent = jobs[i].opts.ExtractContent || s.ExtractContent
+		if jobs[i].opts.Language == "" {
+			jobs[i].opts.Language = s.Language
+		}
+	}
+	return jobs
+}
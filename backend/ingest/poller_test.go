@@ -0,0 +1,128 @@
+package ingest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/frogfromlake/Orbitalone/backend/feeds"
+)
+
+// openTestDB points the feeds package at a private in-memory database.
+func openTestDB(t *testing.T) {
+	t.Helper()
+	if err := feeds.Open("file:" + t.Name() + "?mode=memory&cache=shared&_pragma=busy_timeout(5000)"); err != nil {
+		t.Fatalf("opening test database: %v", err)
+	}
+}
+
+const testFeed = `<?xml version="1.0"?>
+<rss version="2.0"><channel><title>Test</title><language>en</language>
+<item><guid>%[1]s-1</guid><title>First story of %[1]s</title><link>https://example.com/%[1]s/1</link></item>
+<item><guid>%[1]s-2</guid><title>Second story of %[1]s</title><link>https://example.com/%[1]s/2</link></item>
+</channel></rss>`
+
+func TestPollerIngestsWithBoundedWorkers(t *testing.T) {
+	openTestDB(t)
+
+	var inFlight, maxInFlight, requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			m := maxInFlight.Load()
+			if n <= m || maxInFlight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond) // let the workers overlap
+		fmt.Fprintf(w, testFeed, r.URL.Path[1:])
+	}))
+	defer server.Close()
+
+	var sources []feeds.FeedSource
+	for i := range 6 {
+		sources = append(sources, feeds.FeedSource{URL: fmt.Sprintf("%s/feed%d", server.URL, i), Enabled: true})
+	}
+	sources = append(sources, feeds.FeedSource{URL: server.URL + "/disabled", Enabled: false})
+	if err := feeds.SetFeedSources("DE", sources, ""); err != nil {
+		t.Fatal(err)
+	}
+	// A URL configured for two countries is fetched once
+	if err := feeds.SetFeedSources("AT", sources[:1], ""); err != nil {
+		t.Fatal(err)
+	}
+
+	p := NewPoller(Config{Interval: time.Hour, Workers: 2}, nil)
+	p.Start(context.Background())
+	defer p.Stop()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for requests.Load() < 6 || inFlight.Load() > 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("only %d feeds fetched", requests.Load())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	p.Stop()
+
+	if got := requests.Load(); got != 6 {
+		t.Errorf("fetched %d times, want each of the 6 enabled feeds once", got)
+	}
+	if got := maxInFlight.Load(); got != 2 {
+		t.Errorf("at most %d feeds fetched at once, want 2", got)
+	}
+	for _, s := range sources[:6] {
+		if stored, err := feeds.GetArticlesByFeed(s.URL, 10); err != nil || len(stored) != 2 {
+			t.Errorf("%s: stored %d articles (%v), want 2", s.URL, len(stored), err)
+		}
+	}
+}
+
+func TestPollerStopCancelsFetches(t *testing.T) {
+	openTestDB(t)
+
+	started := make(chan struct{}, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-r.Context().Done() // a publisher that never answers
+	}))
+	defer server.Close()
+
+	if err := feeds.SetFeedSources("DE", []feeds.FeedSource{{URL: server.URL + "/slow", Enabled: true}}, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	p := NewPoller(Config{Interval: time.Hour, Workers: 1}, nil)
+	p.Start(context.Background())
+	<-started
+
+	stopped := make(chan struct{})
+	go func() {
+		p.Stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Stop did not return while a fetch was in flight")
+	}
+
+	// Shutting down is not the feed's fault
+	state, err := feeds.GetFeedState(server.URL + "/slow")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.ConsecutiveFailures != 0 {
+		t.Errorf("cancelled fetch counted as %d failures", state.ConsecutiveFailures)
+	}
+}
+
+func TestStopWithoutStart(t *testing.T) {
+	NewPoller(Config{}, nil).Stop()
+}
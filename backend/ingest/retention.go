@@ -0,0 +1,40 @@
+package ingest
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/frogfromlake/Orbitalone/backend/feeds"
+)
+
+// pruneInterval is how often old articles are deleted.
+const pruneInterval = 6 * time.Hour
+
+// pruneIfDue deletes articles older than the retention period and translations unused for
+// as long, at most once per pruneInterval.
+func (p *Poller) pruneIfDue(ctx context.Context) {
+	if p.cfg.Retention <= 0 || ctx.Err() != nil || time.Since(p.lastPrune) < pruneInterval {
+		return
+	}
+	p.lastPrune = time.Now()
+
+	before := time.Now().Add(-p.cfg.Retention)
+	pruned, err := feeds.PruneArticles(before)
+	if err != nil {
+		log.Printf("⚠️ %v", err)
+		return
+	}
+	if pruned > 0 {
+		log.Printf("🧹 Pruned %d articles older than %v", pruned, p.cfg.Retention)
+	}
+
+	purged, err := feeds.PurgeTranslations(feeds.TranslationFilter{UnusedBefore: before})
+	if err != nil {
+		log.Printf("⚠️ %v", err)
+		return
+	}
+	if purged > 0 {
+		log.Printf("🧹 Purged %d translations unused for %v", purged, p.cfg.Retention)
+	}
+}
@@ -0,0 +1,197 @@
+package localization
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/frogfromlake/Orbitalone/backend/feeds"
+)
+
+// ErrBudgetExceeded is returned when a batch would push usage past the hard limit.
+var ErrBudgetExceeded = errors.New("translation budget exhausted")
+
+// BudgetLevel tells callers how much translation the remaining budget allows.
+type BudgetLevel string
+
+const (
+	BudgetOK   BudgetLevel = "ok"   // translate everything
+	BudgetSoft BudgetLevel = "soft" // near the limit: translate titles only
+	BudgetHard BudgetLevel = "hard" // past the limit: serve originals
+)
+
+// BudgetConfig limits the characters sent to a provider. A zero limit is unlimited.
+// The thresholds are fractions of the limits at which the soft and hard levels start.
+type BudgetConfig struct {
+	Daily   int64
+	Monthly int64
+	Soft    float64
+	Hard    float64
+}
+
+// BudgetPeriod is the local character accounting of one period.
+type BudgetPeriod struct {
+	Used  int64 `json:"used"`
+	Limit int64 `json:"limit,omitempty"` // 0 means unlimited
+}
+
+// BudgetStatus is the local character accounting of a provider.
+type BudgetStatus struct {
+	Provider      string       `json:"provider"`
+	Level         BudgetLevel  `json:"level"`
+	Day           BudgetPeriod `json:"day"`
+	Month         BudgetPeriod `json:"month"`
+	SoftThreshold float64      `json:"softThreshold"`
+	HardThreshold float64      `json:"hardThreshold"`
+}
+
+// BudgetConfigFromEnv reads the budget from TRANSLATION_BUDGET_DAILY, TRANSLATION_BUDGET_MONTHLY,
+// TRANSLATION_BUDGET_SOFT (default 0.8) and TRANSLATION_BUDGET_HARD (default 1.0).
+// The monthly limit defaults to defaultMonthly, e.g. the DeepL free tier.
+func BudgetConfigFromEnv(defaultMonthly int64) BudgetConfig {
+	return BudgetConfig{
+		Daily:   envInt("TRANSLATION_BUDGET_DAILY", 0),
+		Monthly: envInt("TRANSLATION_BUDGET_MONTHLY", defaultMonthly),
+		Soft:    envFloat("TRANSLATION_BUDGET_SOFT", 0.8),
+		Hard:    envFloat("TRANSLATION_BUDGET_HARD", 1.0),
+	}
+}
+
+// budgetTranslator counts the characters a provider bills and refuses batches past the
+// hard limit. It sits below the cache, so only texts actually sent are counted. A batch
+// is reserved in full up front; when it fails, the texts that never reached the provider
+// are given back.
+type budgetTranslator struct {
+	next Translator
+	cfg  BudgetConfig
+	mu   sync.Mutex // serializes check-and-reserve
+}
+
+// WithBudget wraps a provider with local character accounting.
+func WithBudget(t Translator, cfg BudgetConfig) Translator {
+	return &budgetTranslator{next: t, cfg: cfg}
+}
+
+func (b *budgetTranslator) Name() string { return b.next.Name() }
+
+// Unwrap returns the underlying provider.
+func (b *budgetTranslator) Unwrap() Translator { return b.next }
+
+func (b *budgetTranslator) TranslateBatch(ctx context.Context, texts []string, sourceLang, targetLang string, format Format) ([]string, error) {
+	chars := countChars(texts)
+
+	// Reserve the characters before sending, so concurrent batches can't overshoot together
+	b.mu.Lock()
+	day, month, err := feeds.GetTranslationUsage(b.next.Name())
+	if err != nil {
+		log.Printf("⚠️  Failed to load translation usage: %v", err)
+	}
+	if b.exceeds(day+chars, b.cfg.Daily) || b.exceeds(month+chars, b.cfg.Monthly) {
+		b.mu.Unlock()
+		return nil, fmt.Errorf("%w: %d characters requested", ErrBudgetExceeded, chars)
+	}
+	if err := feeds.AddTranslationUsage(b.next.Name(), chars); err != nil {
+		log.Printf("⚠️  %v", err)
+	}
+	b.mu.Unlock()
+
+	out, err := b.next.TranslateBatch(ctx, texts, sourceLang, targetLang, format)
+	if err != nil {
+		// Only texts that never reached the provider are certainly not billed
+		unsent := texts
+		var batchErr *BatchError
+		if errors.As(err, &batchErr) {
+			unsent = texts[min(batchErr.Sent, len(texts)):]
+		}
+		if refund := countChars(unsent); refund > 0 {
+			if undoErr := feeds.AddTranslationUsage(b.next.Name(), -refund); undoErr != nil {
+				log.Printf("⚠️  %v", undoErr)
+			}
+		}
+		return out, err
+	}
+	return out, nil
+}
+
+// countChars returns the number of characters in texts, as providers bill them.
+func countChars(texts []string) int64 {
+	var chars int64
+	for _, text := range texts {
+		chars += int64(utf8.RuneCountInString(text))
+	}
+	return chars
+}
+
+// Status returns the current local accounting.
+func (b *budgetTranslator) Status() (BudgetStatus, error) {
+	day, month, err := feeds.GetTranslationUsage(b.next.Name())
+	if err != nil {
+		return BudgetStatus{}, err
+	}
+
+	status := BudgetStatus{
+		Provider:      b.next.Name(),
+		Level:         BudgetOK,
+		Day:           BudgetPeriod{Used: day, Limit: b.cfg.Daily},
+		Month:         BudgetPeriod{Used: month, Limit: b.cfg.Monthly},
+		SoftThreshold: b.cfg.Soft,
+		HardThreshold: b.cfg.Hard,
+	}
+	for _, p := range []BudgetPeriod{status.Day, status.Month} {
+		switch {
+		case p.Limit > 0 && float64(p.Used) >= b.cfg.Hard*float64(p.Limit):
+			status.Level = BudgetHard
+		case status.Level == BudgetOK && p.Limit > 0 && float64(p.Used) >= b.cfg.Soft*float64(p.Limit):
+			status.Level = BudgetSoft
+		}
+	}
+	return status, nil
+}
+
+// exceeds reports whether used characters are past the hard threshold of limit.
+func (b *budgetTranslator) exceeds(used, limit int64) bool {
+	return limit > 0 && float64(used) > b.cfg.Hard*float64(limit)
+}
+
+// Budget returns the local accounting of the budget guard behind t, if there is one.
+func Budget(t Translator) (BudgetStatus, bool) {
+	for t != nil {
+		if b, ok := t.(*budgetTranslator); ok {
+			status, err := b.Status()
+			if err != nil {
+				log.Printf("⚠️  Failed to load translation budget: %v", err)
+				return BudgetStatus{}, false
+			}
+			return status, true
+		}
+		wrapper, ok := t.(interface{ Unwrap() Translator })
+		if !ok {
+			break
+		}
+		t = wrapper.Unwrap()
+	}
+	return BudgetStatus{}, false
+}
+
+// envInt reads an integer environment variable, returning def when it is unset or invalid.
+func envInt(key string, def int64) int64 {
+	v, err := strconv.ParseInt(os.Getenv(key), 10, 64)
+	if err != nil || v < 0 {
+		return def
+	}
+	return v
+}
+
+// envFloat reads a float environment variable, returning def when it is unset or invalid.
+func envFloat(key string, def float64) float64 {
+	v, err := strconv.ParseFloat(os.Getenv(key), 64)
+	if err != nil || v <= 0 {
+		return def
+	}
+	return v
+}
@@ -0,0 +1,134 @@
+package localization
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/frogfromlake/Orbitalone/backend/feeds"
+)
+
+// failingProvider fails every batch after the first sent texts reached it.
+// With sent < 0 it fails with a plain error, as if nothing was known about the batch.
+type failingProvider struct {
+	sent int
+}
+
+func (f failingProvider) Name() string { return "failing" }
+
+func (f failingProvider) TranslateBatch(ctx context.Context, texts []string, sourceLang, targetLang string, format Format) ([]string, error) {
+	err := errors.New("timeout awaiting response")
+	if f.sent < 0 {
+		return nil, err
+	}
+	return nil, &BatchError{Sent: f.sent, Err: err}
+}
+
+func TestBudgetRefundsUnsentTexts(t *testing.T) {
+	texts := []string{"aaaa", "bbbbbb", "cc"} // 12 characters
+	tests := []struct {
+		name     string
+		sent     int
+		wantUsed int64
+	}{
+		{"nothing sent", 0, 0},
+		{"first text sent", 1, 4},
+		{"all sent", 3, 12},
+		{"unknown failure", -1, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			openTestDB(t)
+			b := WithBudget(failingProvider{sent: tt.sent}, BudgetConfig{Monthly: 100, Soft: 0.8, Hard: 1})
+
+			if _, err := b.TranslateBatch(context.Background(), texts, "DE", "EN", FormatText); err == nil {
+				t.Fatal("expected the batch to fail")
+			}
+			day, month, err := feeds.GetTranslationUsage("failing")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if day != tt.wantUsed || month != tt.wantUsed {
+				t.Errorf("usage = %d today, %d this month, want %d", day, month, tt.wantUsed)
+			}
+		})
+	}
+}
+
+func TestBudgetLevels(t *testing.T) {
+	tests := []struct {
+		name      string
+		cfg       BudgetConfig
+		used      int64
+		wantLevel BudgetLevel
+	}{
+		{"unlimited", BudgetConfig{Soft: 0.8, Hard: 1}, 1_000_000, BudgetOK},
+		{"below soft", BudgetConfig{Monthly: 1000, Soft: 0.8, Hard: 1}, 799, BudgetOK},
+		{"at soft", BudgetConfig{Monthly: 1000, Soft: 0.8, Hard: 1}, 800, BudgetSoft},
+		{"at hard", BudgetConfig{Monthly: 1000, Soft: 0.8, Hard: 1}, 1000, BudgetHard},
+		{"lowered hard threshold", BudgetConfig{Monthly: 1000, Soft: 0.5, Hard: 0.9}, 900, BudgetHard},
+		{"daily limit is stricter", BudgetConfig{Daily: 100, Monthly: 1000, Soft: 0.8, Hard: 1}, 100, BudgetHard},
+		{"daily soft, monthly fine", BudgetConfig{Daily: 100, Monthly: 100_000, Soft: 0.8, Hard: 1}, 90, BudgetSoft},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			openTestDB(t)
+			if err := feeds.AddTranslationUsage("fake", tt.used); err != nil {
+				t.Fatal(err)
+			}
+
+			status, ok := Budget(WithCache(WithBudget(&fakeProvider{}, tt.cfg)))
+			if !ok {
+				t.Fatal("no budget found behind the cache")
+			}
+			if status.Level != tt.wantLevel || status.Day.Used != tt.used || status.Month.Used != tt.used {
+				t.Errorf("status = %+v, want level %s with %d used", status, tt.wantLevel, tt.used)
+			}
+		})
+	}
+
+	if _, ok := Budget(WithCache(Echo{})); ok {
+		t.Error("Echo reported a budget")
+	}
+}
+
+func TestBudgetRejectsBatchesPastHardLimit(t *testing.T) {
+	openTestDB(t)
+	provider := &fakeProvider{}
+	b := WithBudget(provider, BudgetConfig{Monthly: 20, Soft: 0.8, Hard: 1})
+	ctx := context.Background()
+
+	if _, err := b.TranslateBatch(ctx, []string{"zwölf Zeichen"}, "DE", "EN", FormatText); err != nil {
+		t.Fatal(err)
+	}
+	// 13 + 9 characters would pass the limit of 20
+	_, err := b.TranslateBatch(ctx, []string{"neun Ztn."}, "DE", "EN", FormatText)
+	if !errors.Is(err, ErrBudgetExceeded) {
+		t.Fatalf("err = %v, want ErrBudgetExceeded", err)
+	}
+	if len(provider.sent) != 1 {
+		t.Errorf("the rejected batch reached the provider")
+	}
+	// Smaller batches still fit
+	if _, err := b.TranslateBatch(ctx, []string{"sieben"}, "DE", "EN", FormatText); err != nil {
+		t.Errorf("a batch within the limit failed: %v", err)
+	}
+	if _, month, _ := feeds.GetTranslationUsage("fake"); month != 19 {
+		t.Errorf("used %d characters, want 19 (counted in runes)", month)
+	}
+}
+
+func TestBudgetConfigFromEnv(t *testing.T) {
+	t.Setenv("TRANSLATION_BUDGET_DAILY", "5000")
+	t.Setenv("TRANSLATION_BUDGET_MONTHLY", "")
+	t.Setenv("TRANSLATION_BUDGET_SOFT", "0.5")
+	t.Setenv("TRANSLATION_BUDGET_HARD", "-1") // invalid: default
+
+	want := BudgetConfig{Daily: 5000, Monthly: deepLFreeCharacters, Soft: 0.5, Hard: 1}
+	if got := BudgetConfigFromEnv(deepLDefaultBudget("key:fx")); got != want {
+		t.Errorf("config = %+v, want %+v", got, want)
+	}
+	if got := deepLDefaultBudget("pro-key"); got != 0 {
+		t.Errorf("Pro keys have a default budget of %d, want none", got)
+	}
+}
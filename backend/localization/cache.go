@@ -0,0 +1,284 @@
+package localization
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/frogfromlake/Orbitalone/backend/feeds"
+	"github.com/patrickmn/go-cache"
+)
+
+// cachedTranslator remembers translations so repeated headlines never reach the provider
+// twice, and skips texts that are already in the target language.
+// Lookups go through an in-memory layer (24h) in front of the persistent translations
+// table, which survives restarts and counts hits. Cached strings are looked up
+// individually; only the misses of a batch are sent on. Hits are tallied in memory and,
+// once Start was called, written to the table every hitFlushInterval, so cache hits
+// never wait on SQLite.
+type cachedTranslator struct {
+	next  Translator
+	cache *cache.Cache
+
+	hitsMu sync.Mutex
+	hits   map[string]int // key -> hits not yet written to the translations table
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// hitFlushInterval is how often tallied cache hits are written to the translations table.
+const hitFlushInterval = time.Minute
+
+// WithCache wraps a translator with the in-memory and persistent translation caches.
+// Call StartCaches to have the tallied hits saved periodically.
+func WithCache(t Translator) Translator {
+	return &cachedTranslator{
+		next:  t,
+		cache: cache.New(24*time.Hour, 1*time.Hour),
+		hits:  make(map[string]int),
+	}
+}
+
+// Start launches the loop saving tallied hits every hitFlushInterval.
+func (c *cachedTranslator) Start(ctx context.Context) {
+	ctx, c.cancel = context.WithCancel(ctx)
+	c.done = make(chan struct{})
+
+	go func() {
+		defer close(c.done)
+		ticker := time.NewTicker(hitFlushInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				c.SaveHits()
+			}
+		}
+	}()
+}
+
+// Stop ends the loop and blocks until it has exited. Hits tallied since the last
+// save are kept until the next SaveHits.
+func (c *cachedTranslator) Stop() {
+	if c.cancel == nil {
+		return
+	}
+	c.cancel()
+	<-c.done
+}
+
+func (c *cachedTranslator) Name() string { return c.next.Name() }
+
+// Unwrap returns the underlying provider.
+func (c *cachedTranslator) Unwrap() Translator { return c.next }
+
+// Flush empties the in-memory layer, e.g. after translations were purged from the database.
+func (c *cachedTranslator) Flush() { c.cache.Flush() }
+
+// SaveHits writes the tallied cache hits to the translations table.
+func (c *cachedTranslator) SaveHits() {
+	c.hitsMu.Lock()
+	hits := c.hits
+	c.hits = make(map[string]int)
+	c.hitsMu.Unlock()
+
+	if err := feeds.TouchTranslations(hits, time.Now()); err != nil {
+		log.Printf("⚠️  Failed to record translation cache hits: %v", err)
+	}
+}
+
+// countHits tallies cache hits until the next SaveHits.
+func (c *cachedTranslator) countHits(keys []string) {
+	c.hitsMu.Lock()
+	defer c.hitsMu.Unlock()
+	for _, key := range keys {
+		c.hits[key]++
+	}
+}
+
+func (c *cachedTranslator) TranslateBatch(ctx context.Context, texts []string, sourceLang, targetLang string, format Format) ([]string, error) {
+	out := make([]string, len(texts))
+	for i, text := range texts {
+		out[i] = normalizeText(text)
+	}
+
+	// ⛔ Skip translation if already in the target language
+	if SameLanguage(sourceLang, targetLang) {
+		log.Printf("↩️  Skipped translation of %d texts (already %s)", len(texts), targetLang)
+		return out, nil
+	}
+
+	var (
+		hitKeys []string                  // served from either layer, counted in the database
+		missing []string                  // unique keys not found in memory
+		slots   = make(map[string][]int)  // key -> output indexes, so duplicates are looked up once
+		sources = make(map[string]string) // key -> normalized source text
+	)
+	for i, text := range out {
+		if text == "" {
+			continue
+		}
+		key := TranslationKey(sourceLang, targetLang, format, text)
+		if cached, found := c.cache.Get(key); found {
+			out[i] = cached.(string)
+			hitKeys = append(hitKeys, key)
+			continue
+		}
+		if _, queued := slots[key]; !queued {
+			missing = append(missing, key)
+			sources[key] = text
+		}
+		slots[key] = append(slots[key], i)
+	}
+
+	// 💾 Second layer: translations persisted by earlier runs
+	if len(missing) > 0 {
+		stored, err := feeds.GetTranslations(missing)
+		if err != nil {
+			log.Printf("⚠️  Failed to load stored translations: %v", err)
+		}
+		var remaining []string
+		for _, key := range missing {
+			text, ok := stored[key]
+			if !ok {
+				remaining = append(remaining, key)
+				continue
+			}
+			c.cache.Set(key, text, cache.DefaultExpiration)
+			for _, i := range slots[key] {
+				out[i] = text
+				hitKeys = append(hitKeys, key)
+			}
+		}
+		missing = remaining
+	}
+
+	if len(hitKeys) > 0 {
+		log.Printf("🧠 Cache hit for %d/%d texts (%s→%s)", len(hitKeys), len(texts), sourceLang, targetLang)
+		c.countHits(hitKeys)
+	}
+	if len(missing) == 0 {
+		return out, nil
+	}
+
+	pending := make([]string, len(missing))
+	for j, key := range missing {
+		pending[j] = sources[key]
+	}
+
+	log.Printf("🌍 Translating %d texts (%s→%s) via %s", len(pending), sourceLang, targetLang, c.next.Name())
+	translated, err := c.next.TranslateBatch(ctx, pending, sourceLang, targetLang, format)
+	if err != nil {
+		// Keep the translations that came back before the failure; they are paid for
+		var batchErr *BatchError
+		if errors.As(err, &batchErr) {
+			n := min(batchErr.Translated, len(translated))
+			c.remember(missing[:n], pending[:n], translated[:n], sourceLang, targetLang)
+		}
+		return nil, err
+	}
+
+	c.remember(missing, pending, translated, sourceLang, targetLang)
+	for j, key := range missing {
+		for _, i := range slots[key] {
+			out[i] = translated[j]
+		}
+	}
+	return out, nil
+}
+
+// remember stores new translations in both layers. keys, sources and translated are
+// parallel slices.
+func (c *cachedTranslator) remember(keys, sources, translated []string, sourceLang, targetLang string) {
+	rows := make([]feeds.Translation, len(keys))
+	for j, key := range keys {
+		c.cache.Set(key, translated[j], cache.DefaultExpiration)
+		rows[j] = feeds.Translation{
+			Key:            key,
+			SourceLang:     strings.ToUpper(sourceLang),
+			TargetLang:     strings.ToUpper(targetLang),
+			SourceText:     sources[j],
+			TranslatedText: translated[j],
+			Provider:       c.next.Name(),
+		}
+	}
+	if err := feeds.SaveTranslations(rows); err != nil {
+		log.Printf("⚠️  Failed to persist translations: %v", err)
+	}
+}
+
+// FlushCaches empties the in-memory layers of t and any translators it wraps.
+func FlushCaches(t Translator) {
+	eachLayer(t, func(t Translator) {
+		if f, ok := t.(interface{ Flush() }); ok {
+			f.Flush()
+		}
+	})
+}
+
+// SaveHits writes the cache hits tallied by t and any translators it wraps,
+// e.g. before shutting down.
+func SaveHits(t Translator) {
+	eachLayer(t, func(t Translator) {
+		if s, ok := t.(interface{ SaveHits() }); ok {
+			s.SaveHits()
+		}
+	})
+}
+
+// StartCaches starts saving the hits of the caches in t and any translators it wraps
+// periodically, until ctx is cancelled or StopCaches is called.
+func StartCaches(ctx context.Context, t Translator) {
+	eachLayer(t, func(t Translator) {
+		if c, ok := t.(*cachedTranslator); ok {
+			c.Start(ctx)
+		}
+	})
+}
+
+// StopCaches stops the loops started by StartCaches and waits for them to exit.
+func StopCaches(t Translator) {
+	eachLayer(t, func(t Translator) {
+		if c, ok := t.(*cachedTranslator); ok {
+			c.Stop()
+		}
+	})
+}
+
+// eachLayer calls fn for t and every translator it wraps, outermost first.
+func eachLayer(t Translator, fn func(Translator)) {
+	for t != nil {
+		fn(t)
+		wrapper, ok := t.(interface{ Unwrap() Translator })
+		if !ok {
+			return
+		}
+		t = wrapper.Unwrap()
+	}
+}
+
+// TranslationKey identifies a translation by language pair, format and normalized text.
+// It is the primary key of the persistent translations table. Plain-text keys are
+// unchanged from before formats existed, so stored translations stay valid.
+func TranslationKey(sourceLang, targetLang string, format Format, text string) string {
+	key := strings.ToUpper(sourceLang) + "\x00" + strings.ToUpper(targetLang) + "\x00" + normalizeText(text)
+	if format == FormatHTML {
+		key += "\x00html"
+	}
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:16])
+}
+
+// normalizeText trims a text and collapses runs of whitespace, so formatting
+// differences between feeds do not produce separate cache entries.
+func normalizeText(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
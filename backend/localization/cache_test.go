@@ -0,0 +1,151 @@
+package localization
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/frogfromlake/Orbitalone/backend/feeds"
+)
+
+// openTestDB points the feeds package at a private in-memory database.
+func openTestDB(t *testing.T) {
+	t.Helper()
+	if err := feeds.Open("file:" + t.Name() + "?mode=memory&cache=shared&_pragma=busy_timeout(5000)"); err != nil {
+		t.Fatalf("opening test database: %v", err)
+	}
+}
+
+// fakeProvider prefixes texts with the target language and records what it was sent.
+// With failAfter > 0 it translates only that many texts of a batch and fails the rest.
+type fakeProvider struct {
+	sent      [][]string
+	failAfter int
+}
+
+func (f *fakeProvider) Name() string { return "fake" }
+
+func (f *fakeProvider) TranslateBatch(ctx context.Context, texts []string, sourceLang, targetLang string, format Format) ([]string, error) {
+	f.sent = append(f.sent, texts)
+	var out []string
+	for i, text := range texts {
+		if f.failAfter > 0 && i == f.failAfter {
+			return out, &BatchError{Translated: i, Sent: len(texts), Err: errors.New("connection reset")}
+		}
+		out = append(out, targetLang+" "+text)
+	}
+	return out, nil
+}
+
+func TestCacheLayers(t *testing.T) {
+	openTestDB(t)
+	ctx := context.Background()
+	provider := &fakeProvider{}
+	first := WithCache(provider)
+
+	out, err := first.TranslateBatch(ctx, []string{"Guten Morgen", " Guten   Morgen ", "Gute Nacht", ""}, "DE", "EN", FormatText)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(out, "|"); got != "EN Guten Morgen|EN Guten Morgen|EN Gute Nacht|" {
+		t.Errorf("out = %q", got)
+	}
+	// Spacing variants are one text; empty texts are never sent
+	if len(provider.sent) != 1 || len(provider.sent[0]) != 2 {
+		t.Fatalf("provider was sent %q, want the two distinct texts once", provider.sent)
+	}
+
+	// Served from memory
+	if _, err := first.TranslateBatch(ctx, []string{"Gute Nacht"}, "DE", "EN", FormatText); err != nil || len(provider.sent) != 1 {
+		t.Errorf("memory hit reached the provider (%v)", err)
+	}
+	// Another target language or format is another entry
+	if _, err := first.TranslateBatch(ctx, []string{"Gute Nacht"}, "DE", "FR", FormatText); err != nil || len(provider.sent) != 2 {
+		t.Errorf("another target language was served from the cache (%v)", err)
+	}
+	if _, err := first.TranslateBatch(ctx, []string{"Gute Nacht"}, "DE", "EN", FormatHTML); err != nil || len(provider.sent) != 3 {
+		t.Errorf("HTML was served from the plain text entry (%v)", err)
+	}
+	// Nothing to do within one language
+	if out, _ := first.TranslateBatch(ctx, []string{" Hallo  Welt"}, "DE", "DE", FormatText); out[0] != "Hallo Welt" || len(provider.sent) != 3 {
+		t.Errorf("same-language batch = %q, sent %d batches", out, len(provider.sent))
+	}
+
+	// After a restart the translations come from the database
+	restarted := &fakeProvider{}
+	out, err = WithCache(restarted).TranslateBatch(ctx, []string{"Gute Nacht", "Guten Morgen"}, "DE", "EN", FormatText)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(restarted.sent) != 0 || out[0] != "EN Gute Nacht" {
+		t.Errorf("after restart: out %q, provider was sent %q", out, restarted.sent)
+	}
+
+	// Purged translations are fetched again once the memory layer is flushed
+	if _, err := feeds.PurgeAllTranslations(); err != nil {
+		t.Fatal(err)
+	}
+	FlushCaches(first)
+	if _, err := first.TranslateBatch(ctx, []string{"Gute Nacht"}, "DE", "EN", FormatText); err != nil || len(provider.sent) != 4 {
+		t.Errorf("flushed entry was not fetched again (%v)", err)
+	}
+}
+
+func TestCacheKeepsPartialResults(t *testing.T) {
+	openTestDB(t)
+	provider := &fakeProvider{failAfter: 2}
+	c := WithCache(provider)
+
+	texts := []string{"eins", "zwei", "drei"}
+	if _, err := c.TranslateBatch(context.Background(), texts, "DE", "EN", FormatText); err == nil {
+		t.Fatal("expected the failed batch to fail")
+	}
+
+	provider.failAfter = 0
+	out, err := c.TranslateBatch(context.Background(), texts, "DE", "EN", FormatText)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(out, ","); got != "EN eins,EN zwei,EN drei" {
+		t.Errorf("out = %q", got)
+	}
+	if len(provider.sent) != 2 || len(provider.sent[1]) != 1 || provider.sent[1][0] != "drei" {
+		t.Errorf("provider was sent %q, want only the untranslated text again", provider.sent)
+	}
+
+	stored, err := feeds.GetTranslations([]string{TranslationKey("DE", "EN", FormatText, "eins")})
+	if err != nil || len(stored) != 1 {
+		t.Errorf("partial result not persisted: %v, %v", stored, err)
+	}
+}
+
+func TestCacheSavesHitsAfterStop(t *testing.T) {
+	openTestDB(t)
+	c := WithCache(&fakeProvider{})
+	StartCaches(context.Background(), c)
+
+	texts := []string{"Hallo", "Welt", "Hallo"}
+	for range 3 {
+		if _, err := c.TranslateBatch(context.Background(), texts, "DE", "EN", FormatText); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Stop must return without waiting for the next tick
+	StopCaches(c)
+	SaveHits(c)
+
+	rows, _, err := feeds.ListTranslations(feeds.TranslationFilter{}, 10, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hits := make(map[string]int64)
+	for _, row := range rows {
+		hits[row.SourceText] = row.Hits
+	}
+	// The first batch translates "Hallo" once and serves its duplicate from the same request
+	if hits["Hallo"] != 4 || hits["Welt"] != 2 {
+		t.Errorf("hits = %v, want Hallo:4 Welt:2", hits)
+	}
+}
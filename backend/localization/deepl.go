@@ -0,0 +1,131 @@
+package localization
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// DeepL accepts at most 50 texts and 128 KiB of form-encoded body per translate request;
+// batches are split below those limits.
+const (
+	deepLMaxTexts = 50
+	deepLMaxBytes = 120 * 1024
+)
+
+// deepLFreeCharacters is the monthly character allowance of the DeepL API free plan.
+const deepLFreeCharacters = 500_000
+
+// deepLDefaultBudget returns the monthly character budget used when none is configured:
+// the free allowance for Free-plan keys (ending in ":fx"), and no limit for Pro keys,
+// which are billed by usage.
+func deepLDefaultBudget(apiKey string) int64 {
+	if strings.HasSuffix(apiKey, ":fx") {
+		return deepLFreeCharacters
+	}
+	return 0
+}
+
+// DeepL translates through the DeepL API.
+type DeepL struct {
+	client *deepLClient
+}
+
+// NewDeepL creates a DeepL translator for the given API key. Keys ending in ":fx"
+// use the Free API endpoint, all others the Pro endpoint; a non-empty baseURL
+// overrides the endpoint.
+func NewDeepL(apiKey, baseURL string) *DeepL {
+	return &DeepL{client: newDeepLClient(apiKey, baseURL)}
+}
+
+func (d *DeepL) Name() string { return "DeepL" }
+
+// TranslateBatch translates texts, sending as few requests as DeepL's limits allow.
+// When a request fails, the translations of the earlier ones are returned with a *BatchError.
+func (d *DeepL) TranslateBatch(ctx context.Context, texts []string, sourceLang, targetLang string, format Format) ([]string, error) {
+	form := deepLForm(sourceLang, targetLang, format)
+	maxBytes := deepLMaxBytes - len(form.Encode())
+
+	out := make([]string, 0, len(texts))
+	for _, chunk := range chunkTexts(texts, deepLMaxTexts, maxBytes, deepLTextSize) {
+		translated, err := d.translate(ctx, chunk, form)
+		if err != nil {
+			sent := len(out)
+			if mayBeBilled(err) {
+				sent += len(chunk)
+			}
+			return out, &BatchError{Translated: len(out), Sent: sent, Err: err}
+		}
+		out = append(out, translated...)
+	}
+	return out, nil
+}
+
+// deepLTextSize returns the bytes a text adds to the form-encoded request body.
+// Percent-encoding makes non-ASCII text up to three times larger.
+func deepLTextSize(text string) int {
+	return len("&text=") + len(url.QueryEscape(text))
+}
+
+// deepLForm returns the translate request parameters other than the texts.
+func deepLForm(sourceLang, targetLang string, format Format) url.Values {
+	form := url.Values{"target_lang": {strings.ToUpper(targetLang)}}
+	// DeepL only accepts base languages as source, but regional variants as target.
+	// Without a source language DeepL detects it.
+	if source := baseLang(sourceLang); source != "" {
+		form.Set("source_lang", source)
+	}
+	if format == FormatHTML {
+		// Keep tags and links intact; DeepL then only translates the text between them
+		form.Set("tag_handling", "html")
+	}
+	return form
+}
+
+// translate sends a single translate request carrying one text parameter per input.
+func (d *DeepL) translate(ctx context.Context, texts []string, params url.Values) ([]string, error) {
+	form := url.Values{"text": texts}
+	for key, values := range params {
+		form[key] = values
+	}
+
+	body, err := d.client.do(ctx, "POST", "/v2/translate", form)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Translations []struct {
+			Text string `json:"text"`
+		} `json:"translations"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to decode DeepL response: %w", err)
+	}
+
+	if len(result.Translations) != len(texts) {
+		return nil, fmt.Errorf("deepl returned %d translations for %d texts", len(result.Translations), len(texts))
+	}
+
+	out := make([]string, len(texts))
+	for i, t := range result.Translations {
+		out[i] = t.Text
+	}
+	return out, nil
+}
+
+// Usage returns the character count and limit of the current DeepL billing period.
+func (d *DeepL) Usage(ctx context.Context) (Usage, error) {
+	body, err := d.client.do(ctx, "GET", "/v2/usage", nil)
+	if err != nil {
+		return Usage{}, err
+	}
+
+	var usage Usage
+	if err := json.Unmarshal(body, &usage); err != nil {
+		return Usage{}, fmt.Errorf("failed to decode DeepL usage: %w", err)
+	}
+	return usage, nil
+}
@@ -0,0 +1,189 @@
+package localization
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	deepLFreeURL = "https://api-free.deepl.com"
+	deepLProURL  = "https://api.deepl.com"
+
+	deepLMaxAttempts   = 4
+	deepLRetryBase     = 500 * time.Millisecond
+	deepLRetryMaxDelay = 30 * time.Second
+)
+
+// ErrDeepLQuotaExceeded is returned when DeepL reports the account's quota as used up (HTTP 456).
+// It wraps ErrBudgetExceeded, so callers treat it like an exhausted local budget.
+var ErrDeepLQuotaExceeded = fmt.Errorf("deepl quota exceeded: %w", ErrBudgetExceeded)
+
+// deepLError is a non-2xx response from the DeepL API.
+type deepLError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *deepLError) Error() string {
+	return fmt.Sprintf("deepl error %d: %s", e.StatusCode, e.Body)
+}
+
+// deepLClient talks to the DeepL API. It authenticates with the Authorization header,
+// picks the Free or Pro endpoint from the key, and retries rate limits and server errors.
+type deepLClient struct {
+	apiKey  string
+	baseURL string
+	http    *http.Client
+}
+
+// newDeepLClient creates a client for the given key. Free-plan keys end in ":fx".
+// baseURL overrides the endpoint (e.g. for a proxy) when not empty.
+func newDeepLClient(apiKey, baseURL string) *deepLClient {
+	if baseURL == "" {
+		baseURL = deepLProURL
+		if strings.HasSuffix(apiKey, ":fx") {
+			baseURL = deepLFreeURL
+		}
+	}
+	return &deepLClient{
+		apiKey:  apiKey,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		http:    &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// do sends a request to path and returns the response body of a 2xx answer.
+// Forms are sent url-encoded in the body of POST requests.
+// 429 and 5xx answers and failures to connect are retried with exponential backoff,
+// honoring Retry-After when DeepL sends it.
+func (c *deepLClient) do(ctx context.Context, method, path string, form url.Values) ([]byte, error) {
+	var lastErr error
+	for attempt := 1; attempt <= deepLMaxAttempts; attempt++ {
+		body, retryAfter, err := c.send(ctx, method, path, form)
+		if err == nil {
+			return body, nil
+		}
+		lastErr = err
+
+		if !retryable(err) || attempt == deepLMaxAttempts || ctx.Err() != nil {
+			break
+		}
+
+		delay := retryAfter
+		if delay <= 0 {
+			delay = backoffDelay(attempt)
+		}
+		log.Printf("🔁 DeepL %s %s failed (%v), retrying in %v", method, path, err, delay)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			// The failed attempt tells more about what happened than the cancellation
+			timer.Stop()
+			return nil, lastErr
+		case <-timer.C:
+		}
+	}
+	return nil, lastErr
+}
+
+// send performs a single request attempt. It returns the Retry-After delay of the answer, if any.
+func (c *deepLClient) send(ctx context.Context, method, path string, form url.Values) ([]byte, time.Duration, error) {
+	var payload io.Reader
+	if form != nil {
+		payload = strings.NewReader(form.Encode())
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, payload)
+	if err != nil {
+		return nil, 0, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Authorization", "DeepL-Auth-Key "+c.apiKey)
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, fmt.Errorf("reading response: %w", err)
+	}
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return body, 0, nil
+	case resp.StatusCode == 456:
+		return nil, 0, ErrDeepLQuotaExceeded
+	default:
+		return nil, parseRetryAfter(resp.Header.Get("Retry-After")), &deepLError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+}
+
+// retryable reports whether a failed attempt may succeed when repeated without being
+// billed twice. Errors after the request was sent, such as timeouts waiting for the
+// answer, are not retried: DeepL may already have translated and counted the texts.
+func retryable(err error) bool {
+	var apiErr *deepLError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == http.StatusTooManyRequests || apiErr.StatusCode >= 500
+	}
+	// Only failures to reach DeepL at all are worth another try
+	return failedToConnect(err)
+}
+
+// mayBeBilled reports whether DeepL may have translated and counted the texts of a failed
+// request. Error answers are not billed and failed connections never reached DeepL;
+// anything else, such as a timeout waiting for the answer, may have been.
+func mayBeBilled(err error) bool {
+	var apiErr *deepLError
+	if errors.As(err, &apiErr) || errors.Is(err, ErrDeepLQuotaExceeded) {
+		return false
+	}
+	return !failedToConnect(err)
+}
+
+// failedToConnect reports whether err is a failure to reach the server at all.
+func failedToConnect(err error) bool {
+	var (
+		opErr  *net.OpError
+		dnsErr *net.DNSError
+	)
+	return errors.As(err, &dnsErr) || errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// backoffDelay returns 0.5s, 1s, 2s, ... with ±20% jitter for the given attempt.
+func backoffDelay(attempt int) time.Duration {
+	delay := deepLRetryBase << (attempt - 1)
+	jitter := 1 + 0.2*(2*rand.Float64()-1)
+	return min(time.Duration(float64(delay)*jitter), deepLRetryMaxDelay)
+}
+
+// parseRetryAfter reads a Retry-After header in seconds or HTTP-date form, capped
+// at the maximum retry delay. It returns 0 when the header is absent or invalid.
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(value); err == nil && secs >= 0 {
+		return min(time.Duration(secs)*time.Second, deepLRetryMaxDelay)
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return min(max(time.Until(at), 0), deepLRetryMaxDelay)
+	}
+	return 0
+}
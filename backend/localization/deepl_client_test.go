@@ -0,0 +1,172 @@
+package localization
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		value    string
+		min, max time.Duration
+	}{
+		{"", 0, 0},
+		{"0", 0, 0},
+		{"7", 7 * time.Second, 7 * time.Second},
+		{"3600", deepLRetryMaxDelay, deepLRetryMaxDelay}, // capped
+		{"-5", 0, 0},
+		{"soon", 0, 0},
+		{time.Now().Add(10 * time.Second).UTC().Format(http.TimeFormat), 8 * time.Second, 10 * time.Second},
+		{time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat), 0, 0},
+	}
+	for _, tt := range tests {
+		if got := parseRetryAfter(tt.value); got < tt.min || got > tt.max {
+			t.Errorf("parseRetryAfter(%q) = %v, want %v..%v", tt.value, got, tt.min, tt.max)
+		}
+	}
+}
+
+func TestBackoffDelay(t *testing.T) {
+	for attempt, base := range map[int]time.Duration{1: 500 * time.Millisecond, 2: time.Second, 3: 2 * time.Second, 10: deepLRetryMaxDelay} {
+		for range 20 {
+			d := backoffDelay(attempt)
+			if d < base*8/10 || d > min(base*12/10, deepLRetryMaxDelay) {
+				t.Fatalf("backoffDelay(%d) = %v, want %v ±20%%", attempt, d, base)
+			}
+		}
+	}
+}
+
+func TestNewDeepLClientEndpoint(t *testing.T) {
+	for _, tt := range []struct{ key, override, want string }{
+		{"abc:fx", "", deepLFreeURL},
+		{"abc", "", deepLProURL},
+		{"abc:fx", "https://proxy.example/deepl/", "https://proxy.example/deepl"},
+	} {
+		if got := newDeepLClient(tt.key, tt.override).baseURL; got != tt.want {
+			t.Errorf("key %q, override %q: %s, want %s", tt.key, tt.override, got, tt.want)
+		}
+	}
+}
+
+// scriptedServer answers the requests it receives with the given statuses in turn.
+func scriptedServer(t *testing.T, statuses ...int) (*deepLClient, *atomic.Int32) {
+	t.Helper()
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(requests.Add(1))
+		if got := r.Header.Get("Authorization"); got != "DeepL-Auth-Key secret" {
+			t.Errorf("Authorization = %q", got)
+		}
+		if r.URL.Query().Has("auth_key") || r.FormValue("auth_key") != "" {
+			t.Error("the key was sent as a parameter")
+		}
+		status := statuses[min(n, len(statuses))-1]
+		if status == http.StatusTooManyRequests {
+			w.Header().Set("Retry-After", "1")
+		}
+		w.WriteHeader(status)
+		w.Write([]byte(`{"character_count": 5, "character_limit": 10}`))
+	}))
+	t.Cleanup(server.Close)
+	return newDeepLClient("secret", server.URL), &requests
+}
+
+func TestDeepLClientRetries(t *testing.T) {
+	tests := []struct {
+		name         string
+		statuses     []int
+		wantRequests int32
+		wantErr      func(error) bool
+	}{
+		{"success", []int{200}, 1, nil},
+		{"rate limited, then served", []int{429, 200}, 2, nil},
+		{"server errors, then served", []int{503, 500, 200}, 3, nil},
+		{"bad request is not retried", []int{400}, 1, func(err error) bool {
+			var apiErr *deepLError
+			return errors.As(err, &apiErr) && apiErr.StatusCode == 400
+		}},
+		{"quota exceeded is not retried", []int{456}, 1, func(err error) bool {
+			return errors.Is(err, ErrDeepLQuotaExceeded) && errors.Is(err, ErrBudgetExceeded)
+		}},
+		{"gives up after the last attempt", []int{502}, deepLMaxAttempts, func(err error) bool {
+			var apiErr *deepLError
+			return errors.As(err, &apiErr) && apiErr.StatusCode == 502
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			client, requests := scriptedServer(t, tt.statuses...)
+			_, err := client.do(context.Background(), "POST", "/v2/translate", map[string][]string{"text": {"Hallo"}})
+
+			if tt.wantErr == nil && err != nil || tt.wantErr != nil && !tt.wantErr(err) {
+				t.Errorf("unexpected error %v", err)
+			}
+			if got := requests.Load(); got != tt.wantRequests {
+				t.Errorf("sent %d requests, want %d", got, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestDeepLUsage(t *testing.T) {
+	client, _ := scriptedServer(t, 200)
+	usage, err := (&DeepL{client: client}).Usage(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if usage != (Usage{CharacterCount: 5, CharacterLimit: 10}) {
+		t.Errorf("usage = %+v", usage)
+	}
+}
+
+func TestRetryableAndBilled(t *testing.T) {
+	dialErr := &net.OpError{Op: "dial", Err: errors.New("connection refused")}
+	readErr := &net.OpError{Op: "read", Err: errors.New("i/o timeout")}
+
+	tests := []struct {
+		name               string
+		err                error
+		retry, mayBeBilled bool
+	}{
+		{"rate limited", &deepLError{StatusCode: 429}, true, false},
+		{"server error", &deepLError{StatusCode: 503}, true, false},
+		{"bad request", &deepLError{StatusCode: 400}, false, false},
+		{"quota exceeded", ErrDeepLQuotaExceeded, false, false},
+		{"DeepL unreachable", fmt.Errorf("request failed: %w", dialErr), true, false},
+		{"unknown host", &net.DNSError{Err: "no such host", Name: "api.deepl.com"}, true, false},
+		{"timeout awaiting the answer", fmt.Errorf("request failed: %w", readErr), false, true},
+		{"context deadline", context.DeadlineExceeded, false, true},
+	}
+	for _, tt := range tests {
+		if got := retryable(tt.err); got != tt.retry {
+			t.Errorf("%s: retryable = %v, want %v", tt.name, got, tt.retry)
+		}
+		if got := mayBeBilled(tt.err); got != tt.mayBeBilled {
+			t.Errorf("%s: mayBeBilled = %v, want %v", tt.name, got, tt.mayBeBilled)
+		}
+	}
+}
+
+func TestDeepLClientCancelledDuringBackoff(t *testing.T) {
+	client, requests := scriptedServer(t, 429) // asks to wait a second
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	_, err := client.do(ctx, "GET", "/v2/usage", nil)
+	var apiErr *deepLError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("err = %v, want the 429 answer rather than the cancellation", err)
+	}
+	if requests.Load() != 1 {
+		t.Errorf("sent %d requests after the cancellation", requests.Load())
+	}
+}
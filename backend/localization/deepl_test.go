@@ -0,0 +1,230 @@
+package localization
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestChunkTexts(t *testing.T) {
+	byteLen := func(text string) int { return len(text) }
+
+	tests := []struct {
+		name     string
+		texts    []string
+		maxTexts int
+		maxBytes int
+		want     [][]string
+	}{
+		{
+			name:     "empty",
+			texts:    nil,
+			maxTexts: 2,
+			maxBytes: 100,
+			want:     nil,
+		},
+		{
+			name:     "fits into one batch",
+			texts:    []string{"a", "b", "c"},
+			maxTexts: 5,
+			maxBytes: 100,
+			want:     [][]string{{"a", "b", "c"}},
+		},
+		{
+			name:     "split by count",
+			texts:    []string{"a", "b", "c", "d", "e"},
+			maxTexts: 2,
+			maxBytes: 100,
+			want:     [][]string{{"a", "b"}, {"c", "d"}, {"e"}},
+		},
+		{
+			name:     "split by size",
+			texts:    []string{"aaaa", "bbbb", "cc", "dddd"},
+			maxTexts: 10,
+			maxBytes: 8,
+			want:     [][]string{{"aaaa", "bbbb"}, {"cc", "dddd"}},
+		},
+		{
+			name:     "exact size fits",
+			texts:    []string{"aaaa", "bbbb"},
+			maxTexts: 10,
+			maxBytes: 8,
+			want:     [][]string{{"aaaa", "bbbb"}},
+		},
+		{
+			name:     "oversized text gets its own batch",
+			texts:    []string{"a", "bbbbbbbbbbbb", "c"},
+			maxTexts: 10,
+			maxBytes: 4,
+			want:     [][]string{{"a"}, {"bbbbbbbbbbbb"}, {"c"}},
+		},
+		{
+			name:     "oversized first text",
+			texts:    []string{"bbbbbbbbbbbb", "c"},
+			maxTexts: 10,
+			maxBytes: 4,
+			want:     [][]string{{"bbbbbbbbbbbb"}, {"c"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := chunkTexts(tt.texts, tt.maxTexts, tt.maxBytes, byteLen)
+			if !equalChunks(got, tt.want) {
+				t.Errorf("chunkTexts = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestChunkTextsDeepLEncoding checks that batches stay below DeepL's body limit once
+// their texts are percent-encoded, which roughly triples non-ASCII text.
+func TestChunkTextsDeepLEncoding(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+	}{
+		{"ascii", strings.Repeat("Breaking news from the coast. ", 100)},
+		{"cyrillic", strings.Repeat("Срочные новости с побережья. ", 100)},
+		{"japanese", strings.Repeat("沿岸からの速報です。", 200)},
+		{"html", strings.Repeat(`<p>News &amp; <a href="https://example.com/?a=1&b=2">more</a></p>`, 60)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			texts := make([]string, 40)
+			for i := range texts {
+				texts[i] = tt.text
+			}
+			form := deepLForm("RU", "EN-GB", FormatHTML)
+			maxBytes := deepLMaxBytes - len(form.Encode())
+
+			chunks := chunkTexts(texts, deepLMaxTexts, maxBytes, deepLTextSize)
+			total := 0
+			for _, chunk := range chunks {
+				body := deepLForm("RU", "EN-GB", FormatHTML)
+				body["text"] = chunk
+				if size := len(body.Encode()); size > deepLMaxBytes {
+					t.Errorf("batch of %d texts encodes to %d bytes, limit %d", len(chunk), size, deepLMaxBytes)
+				}
+				total += len(chunk)
+			}
+			if total != len(texts) {
+				t.Errorf("batches hold %d texts, want %d", total, len(texts))
+			}
+		})
+	}
+}
+
+func TestDeepLForm(t *testing.T) {
+	tests := []struct {
+		name           string
+		source, target string
+		format         Format
+		want           url.Values
+	}{
+		{"plain text", "de", "en-gb", FormatText, url.Values{"source_lang": {"DE"}, "target_lang": {"EN-GB"}}},
+		{"regional source reduced", "pt-BR", "DE", FormatText, url.Values{"source_lang": {"PT"}, "target_lang": {"DE"}}},
+		{"empty source omitted", "", "EN", FormatText, url.Values{"target_lang": {"EN"}}},
+		{"html", "FR", "EN", FormatHTML, url.Values{"source_lang": {"FR"}, "target_lang": {"EN"}, "tag_handling": {"html"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := deepLForm(tt.source, tt.target, tt.format); got.Encode() != tt.want.Encode() {
+				t.Errorf("deepLForm = %q, want %q", got.Encode(), tt.want.Encode())
+			}
+		})
+	}
+}
+
+// equalChunks reports whether two batch lists hold the same texts in the same order.
+func equalChunks(a, b [][]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if len(a[i]) != len(b[i]) {
+			return false
+		}
+		for j := range a[i] {
+			if a[i][j] != b[i][j] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func TestDeepLPartialBatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		fail     func(w http.ResponseWriter) // answers the second request
+		wantSent int
+	}{
+		{
+			name: "error answer is not billed",
+			fail: func(w http.ResponseWriter) {
+				http.Error(w, `{"message":"bad request"}`, http.StatusBadRequest)
+			},
+			wantSent: deepLMaxTexts,
+		},
+		{
+			name: "connection lost after sending may be billed",
+			fail: func(w http.ResponseWriter) {
+				conn, _, err := w.(http.Hijacker).Hijack()
+				if err == nil {
+					conn.Close()
+				}
+			},
+			wantSent: deepLMaxTexts + 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if requests.Add(1) > 1 {
+					tt.fail(w)
+					return
+				}
+				r.ParseForm()
+				var resp struct {
+					Translations []map[string]string `json:"translations"`
+				}
+				for _, text := range r.PostForm["text"] {
+					resp.Translations = append(resp.Translations, map[string]string{"text": "EN " + text})
+				}
+				json.NewEncoder(w).Encode(resp)
+			}))
+			defer server.Close()
+
+			texts := make([]string, deepLMaxTexts+1)
+			for i := range texts {
+				texts[i] = fmt.Sprintf("Text %d", i)
+			}
+			out, err := NewDeepL("key", server.URL).TranslateBatch(context.Background(), texts, "DE", "EN", FormatText)
+
+			var batchErr *BatchError
+			if !errors.As(err, &batchErr) {
+				t.Fatalf("err = %v, want a *BatchError", err)
+			}
+			if batchErr.Translated != deepLMaxTexts || len(out) != deepLMaxTexts {
+				t.Errorf("translated %d texts, returned %d, want %d", batchErr.Translated, len(out), deepLMaxTexts)
+			}
+			if out[0] != "EN Text 0" {
+				t.Errorf("out[0] = %q", out[0])
+			}
+			if batchErr.Sent != tt.wantSent {
+				t.Errorf("Sent = %d, want %d", batchErr.Sent, tt.wantSent)
+			}
+			if n := requests.Load(); n != 2 {
+				t.Errorf("%d requests, want 2 (no retry)", n)
+			}
+		})
+	}
+}
@@ -0,0 +1,217 @@
+package localization
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// minDetectLetters is the shortest text (in letters) the classifier will judge.
+const minDetectLetters = 20
+
+// minDetectMargin is the average per-n-gram log-likelihood lead the best language
+// needs over the runner-up before a classification is trusted.
+const minDetectMargin = 0.02
+
+// minPriorMargin is the lead another language needs over the expected one to replace it.
+// Text in the wrong language is usually ahead by 0.3 or more; close relatives such as
+// Danish and Norwegian by 0.1 to 0.2.
+const minPriorMargin = 0.1
+
+// languageModel holds the n-gram counts of one language's training sample.
+type languageModel struct {
+	counts map[string]float64
+	total  float64
+}
+
+// models holds one model per Latin-script language, built from languageSamples.
+var models = func() map[string]languageModel {
+	result := make(map[string]languageModel, len(languageSamples))
+	for lang, sample := range languageSamples {
+		m := languageModel{counts: ngramCounts(sample)}
+		for _, c := range m.counts {
+			m.total += c
+		}
+		result[lang] = m
+	}
+	return result
+}()
+
+// NormalizeLanguage turns a declared language such as "fr-BE", "de_CH" or "en-us"
+// into a lower-case ISO 639-1 code, or "" when the value is empty.
+func NormalizeLanguage(declared string) string {
+	lang := strings.ToLower(strings.TrimSpace(declared))
+	lang = strings.Split(strings.ReplaceAll(lang, "_", "-"), "-")[0]
+	switch lang {
+	case "no", "nn":
+		return "nb"
+	}
+	if len(lang) < 2 || len(lang) > 3 {
+		return ""
+	}
+	return lang
+}
+
+// DetectLanguage classifies text and returns its lower-case ISO 639-1 code.
+// Non-Latin scripts are recognized by their characters; Latin-script languages by
+// comparing character n-grams against the built-in language models. The result is false
+// when the text is too short, its script is ambiguous (Cyrillic without a telling letter)
+// or no language is a clear match.
+func DetectLanguage(text string) (string, bool) {
+	return detectLanguage(text, "")
+}
+
+// DetectLanguageWithPrior classifies text that is expected to be in prior, such as the
+// language its feed declares. Another language only wins when the classifier clearly
+// prefers it; text the classifier cannot judge is taken to be in prior.
+func DetectLanguageWithPrior(text, prior string) (string, bool) {
+	prior = NormalizeLanguage(prior)
+	if lang, ok := detectLanguage(text, prior); ok {
+		return lang, true
+	}
+	return prior, prior != ""
+}
+
+// detectLanguage classifies text, keeping the Latin-script language prior unless another
+// leads it by minPriorMargin.
+func detectLanguage(text, prior string) (string, bool) {
+	if lang, ok := detectScript(text); ok {
+		return lang, true
+	}
+
+	// The n-gram models only know Latin-script languages
+	letters, latin := 0, 0
+	for _, r := range text {
+		if unicode.IsLetter(r) {
+			letters++
+			if unicode.Is(unicode.Latin, r) {
+				latin++
+			}
+		}
+	}
+	if latin < minDetectLetters || latin*2 <= letters {
+		return "", false
+	}
+
+	// Naive Bayes with add-one smoothing over the sample's n-grams
+	sample := ngramCounts(text)
+	var n float64
+	for _, c := range sample {
+		n += c
+	}
+
+	type match struct {
+		lang  string
+		score float64
+	}
+	matches := make([]match, 0, len(models))
+	for lang, m := range models {
+		vocabulary := float64(len(m.counts)) + 1
+		var score float64
+		for gram, c := range sample {
+			score += c * math.Log((m.counts[gram]+1)/(m.total+vocabulary))
+		}
+		matches = append(matches, match{lang, score / n})
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i].score > matches[j].score })
+
+	if _, known := models[prior]; known {
+		for _, m := range matches {
+			if m.lang == prior && matches[0].score-m.score < minPriorMargin {
+				return prior, true
+			}
+		}
+	}
+	if matches[0].score-matches[1].score < minDetectMargin {
+		return "", false
+	}
+	return matches[0].lang, true
+}
+
+// detectScript recognizes languages that are identified by their script alone,
+// or nearly so. It only answers when most letters belong to one such script.
+func detectScript(text string) (string, bool) {
+	var (
+		letters                                    int
+		cyrillic, greek, arabic, hangul, kana, han int
+		ukrainian, russian, bulgarian              int
+	)
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		letters++
+		switch {
+		case unicode.Is(unicode.Cyrillic, r):
+			cyrillic++
+			switch unicode.ToLower(r) {
+			case 'і', 'ї', 'є', 'ґ':
+				ukrainian++
+			case 'ы', 'э', 'ё':
+				russian++
+			case 'ъ':
+				bulgarian++
+			}
+		case unicode.Is(unicode.Greek, r):
+			greek++
+		case unicode.Is(unicode.Arabic, r):
+			arabic++
+		case unicode.Is(unicode.Hangul, r):
+			hangul++
+		case unicode.Is(unicode.Hiragana, r), unicode.Is(unicode.Katakana, r):
+			kana++
+		case unicode.Is(unicode.Han, r):
+			han++
+		}
+	}
+	if letters == 0 {
+		return "", false
+	}
+
+	majority := func(n int) bool { return n*2 > letters }
+	switch {
+	case majority(cyrillic):
+		switch {
+		case ukrainian > 0 && ukrainian >= russian:
+			return "uk", true
+		case russian > 0:
+			return "ru", true
+		case bulgarian > 0:
+			return "bg", true
+		}
+		// Without a telling letter the text could be any Cyrillic language
+		return "", false
+	case majority(greek):
+		return "el", true
+	case majority(arabic):
+		return "ar", true
+	case majority(hangul):
+		return "ko", true
+	case kana > 0 && majority(kana+han):
+		return "ja", true
+	case majority(han):
+		return "zh", true
+	}
+	return "", false
+}
+
+// ngramCounts counts the character 1- to 3-grams of text, with words padded by spaces
+// so that word starts and endings are represented. Single letters and pairs keep
+// short headlines with distinctive letters classifiable.
+func ngramCounts(text string) map[string]float64 {
+	counts := make(map[string]float64)
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	}) {
+		runes := []rune(" " + word + " ")
+		for n := 1; n <= 3; n++ {
+			for i := 0; i+n <= len(runes); i++ {
+				if gram := string(runes[i : i+n]); gram != " " {
+					counts[gram]++
+				}
+			}
+		}
+	}
+	return counts
+}
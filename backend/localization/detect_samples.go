@@ -0,0 +1,50 @@
+package localization
+
+// languageSamples are the training texts of the trigram classifier: the first article
+// of the Universal Declaration of Human Rights followed by a short news paragraph,
+// so both formal vocabulary and the function words of headlines are represented.
+var languageSamples = map[string]string{
+	"en": `All human beings are born free and equal in dignity and rights. They are endowed with reason and conscience and should act towards one another in a spirit of brotherhood. The government said on Monday that the new law would come into force next year, after the parliament approved the budget. The president of the country will meet with the ministers of the region to discuss the economic situation and the future of the energy supply. Police say that two people were injured in the attack and that the investigation is still ongoing.`,
+
+	"de": `Alle Menschen sind frei und gleich an Würde und Rechten geboren. Sie sind mit Vernunft und Gewissen begabt und sollen einander im Geist der Brüderlichkeit begegnen. Die Bundesregierung hat am Montag mitgeteilt, dass das neue Gesetz im nächsten Jahr in Kraft treten soll, nachdem der Bundestag den Haushalt beschlossen hat. Der Präsident des Landes wird sich mit den Ministern der Region treffen, um über die wirtschaftliche Lage und die Zukunft der Energieversorgung zu sprechen. Nach Angaben der Polizei wurden bei dem Angriff zwei Menschen verletzt, die Ermittlungen dauern noch an.`,
+
+	"fr": `Tous les êtres humains naissent libres et égaux en dignité et en droits. Ils sont doués de raison et de conscience et doivent agir les uns envers les autres dans un esprit de fraternité. Le gouvernement a annoncé lundi que la nouvelle loi entrera en vigueur l'année prochaine, après l'adoption du budget par le parlement. Le président du pays rencontrera les ministres de la région pour discuter de la situation économique et de l'avenir de l'approvisionnement en énergie. Selon la police, deux personnes ont été blessées lors de l'attaque et l'enquête est toujours en cours.`,
+
+	"es": `Todos los seres humanos nacen libres e iguales en dignidad y derechos y, dotados como están de razón y conciencia, deben comportarse fraternalmente los unos con los otros. El gobierno anunció el lunes que la nueva ley entrará en vigor el próximo año, después de que el parlamento aprobara el presupuesto. El presidente del país se reunirá con los ministros de la región para hablar de la situación económica y del futuro del suministro de energía. Según la policía, dos personas resultaron heridas en el ataque y la investigación sigue abierta.`,
+
+	"it": `Tutti gli esseri umani nascono liberi ed eguali in dignità e diritti. Essi sono dotati di ragione e di coscienza e devono agire gli uni verso gli altri in spirito di fratellanza. Il governo ha annunciato lunedì che la nuova legge entrerà in vigore il prossimo anno, dopo che il parlamento avrà approvato il bilancio. Il presidente del paese incontrerà i ministri della regione per discutere della situazione economica e del futuro dell'approvvigionamento energetico. Secondo la polizia, due persone sono rimaste ferite nell'attacco e le indagini sono ancora in corso.`,
+
+	"pt": `Todos os seres humanos nascem livres e iguais em dignidade e em direitos. Dotados de razão e de consciência, devem agir uns para com os outros em espírito de fraternidade. O governo anunciou na segunda-feira que a nova lei vai entrar em vigor no próximo ano, depois de o parlamento ter aprovado o orçamento. O presidente do país vai reunir-se com os ministros da região para discutir a situação económica e o futuro do abastecimento de energia. Segundo a polícia, duas pessoas ficaram feridas no ataque e a investigação ainda não terminou.`,
+
+	"nl": `Alle mensen worden vrij en gelijk in waardigheid en rechten geboren. Zij zijn begiftigd met verstand en geweten, en behoren zich jegens elkander in een geest van broederschap te gedragen. De regering heeft maandag bekendgemaakt dat de nieuwe wet volgend jaar van kracht wordt, nadat het parlement de begroting heeft goedgekeurd. De president van het land zal met de ministers van de regio overleggen over de economische situatie en de toekomst van de energievoorziening. Volgens de politie raakten bij de aanval twee mensen gewond en het onderzoek is nog niet afgerond.`,
+
+	"pl": `Wszyscy ludzie rodzą się wolni i równi pod względem swej godności i swych praw. Są oni obdarzeni rozumem i sumieniem i powinni postępować wobec innych w duchu braterstwa. Rząd poinformował w poniedziałek, że nowa ustawa wejdzie w życie w przyszłym roku, po tym jak parlament przyjmie budżet. Prezydent kraju spotka się z ministrami regionu, aby omówić sytuację gospodarczą i przyszłość dostaw energii. Według policji w ataku zostały ranne dwie osoby, a śledztwo wciąż trwa.`,
+
+	"cs": `Všichni lidé rodí se svobodní a sobě rovní co do důstojnosti a práv. Jsou nadáni rozumem a svědomím a mají spolu jednat v duchu bratrství. Vláda v pondělí oznámila, že nový zákon vstoupí v platnost příští rok poté, co parlament schválí rozpočet. Prezident země se setká s ministry regionu, aby projednali hospodářskou situaci a budoucnost dodávek energie. Podle policie byli při útoku zraněni dva lidé a vyšetřování stále pokračuje.`,
+
+	"sk": `Všetci ľudia sa rodia slobodní a sebe rovní, čo sa týka ich dôstojnosti a práv. Sú obdarení rozumom a svedomím a majú spolu navzájom jednať v bratskom duchu. Vláda v pondelok oznámila, že nový zákon nadobudne účinnosť budúci rok, keď parlament schváli rozpočet. Prezident krajiny sa stretne s ministrami regiónu, aby prerokovali hospodársku situáciu a budúcnosť dodávok energie. Podľa polície boli pri útoku zranení dvaja ľudia a vyšetrovanie stále pokračuje.`,
+
+	"sl": `Vsi ljudje se rodijo svobodni in imajo enako dostojanstvo in enake pravice. Obdarjeni so z razumom in vestjo in bi morali ravnati drug z drugim kakor bratje. Vlada je v ponedeljek sporočila, da bo novi zakon začel veljati prihodnje leto, potem ko bo državni zbor sprejel proračun. Predsednik države se bo sestal z ministri regije, da bi se pogovorili o gospodarskem položaju in prihodnosti oskrbe z energijo. Po navedbah policije sta bila v napadu ranjena dva človeka, preiskava pa še poteka.`,
+
+	"hu": `Minden emberi lény szabadon születik és egyenlő méltósága és joga van. Az emberek, ésszel és lelkiismerettel bírván, egymással szemben testvéri szellemben kell hogy viseltessenek. A kormány hétfőn bejelentette, hogy az új törvény jövőre lép hatályba, miután a parlament elfogadta a költségvetést. Az ország elnöke találkozik a régió minisztereivel, hogy megvitassák a gazdasági helyzetet és az energiaellátás jövőjét. A rendőrség szerint a támadásban két ember megsérült, a nyomozás még folyamatban van.`,
+
+	"ro": `Toate ființele umane se nasc libere și egale în demnitate și în drepturi. Ele sunt înzestrate cu rațiune și conștiință și trebuie să se comporte unele față de altele în spiritul fraternității. Guvernul a anunțat luni că noua lege va intra în vigoare anul viitor, după ce parlamentul va aproba bugetul. Președintele țării se va întâlni cu miniștrii din regiune pentru a discuta despre situația economică și viitorul aprovizionării cu energie. Potrivit poliției, două persoane au fost rănite în atac, iar ancheta este încă în desfășurare.`,
+
+	"sv": `Alla människor är födda fria och lika i värde och rättigheter. De har utrustats med förnuft och samvete och bör handla gentemot varandra i en anda av broderskap. Regeringen meddelade på måndagen att den nya lagen ska träda i kraft nästa år, efter att riksdagen har godkänt budgeten. Landets president kommer att träffa ministrarna i regionen för att diskutera det ekonomiska läget och framtiden för energiförsörjningen. Enligt polisen skadades två personer i attacken och utredningen pågår fortfarande.`,
+
+	"da": `Alle mennesker er født frie og lige i værdighed og rettigheder. De er udstyret med fornuft og samvittighed, og de bør handle mod hverandre i en broderskabets ånd. Regeringen meddelte mandag, at den nye lov træder i kraft næste år, efter at Folketinget har vedtaget finansloven. Landets præsident vil mødes med ministrene fra regionen for at drøfte den økonomiske situation og fremtiden for energiforsyningen. Ifølge politiet blev to personer såret ved angrebet, og efterforskningen er stadig i gang.`,
+
+	"nb": `Alle mennesker er født frie og med samme menneskeverd og menneskerettigheter. De er utstyrt med fornuft og samvittighet og bør handle mot hverandre i brorskapets ånd. Regjeringen opplyste mandag at den nye loven skal tre i kraft neste år, etter at Stortinget har vedtatt budsjettet. Landets president skal møte ministrene fra regionen for å diskutere den økonomiske situasjonen og fremtiden for energiforsyningen. Ifølge politiet ble to personer skadet i angrepet, og etterforskningen pågår fortsatt.`,
+
+	"fi": `Kaikki ihmiset syntyvät vapaina ja tasavertaisina arvoltaan ja oikeuksiltaan. Heille on annettu järki ja omatunto, ja heidän on toimittava toisiaan kohtaan veljeyden hengessä. Hallitus ilmoitti maanantaina, että uusi laki tulee voimaan ensi vuonna, kun eduskunta on hyväksynyt talousarvion. Maan presidentti tapaa alueen ministerit keskustellakseen taloudellisesta tilanteesta ja energiahuollon tulevaisuudesta. Poliisin mukaan iskussa loukkaantui kaksi ihmistä, ja tutkinta on yhä kesken.`,
+
+	"et": `Kõik inimesed sünnivad vabadena ja võrdsetena oma väärikuselt ja õigustelt. Neile on antud mõistus ja südametunnistus ja nende suhtumist üksteisesse peab kandma vendluse vaim. Valitsus teatas esmaspäeval, et uus seadus jõustub järgmisel aastal pärast seda, kui riigikogu on eelarve heaks kiitnud. Riigi president kohtub piirkonna ministritega, et arutada majanduslikku olukorda ja energiavarustuse tulevikku. Politsei sõnul sai rünnakus viga kaks inimest ja uurimine alles käib.`,
+
+	"lv": `Visi cilvēki piedzimst brīvi un vienlīdzīgi savā pašcieņā un tiesībās. Viņi ir apveltīti ar saprātu un sirdsapziņu, un viņiem jāizturas citam pret citu brālības garā. Valdība pirmdien paziņoja, ka jaunais likums stāsies spēkā nākamgad, kad Saeima būs apstiprinājusi budžetu. Valsts prezidents tiksies ar reģiona ministriem, lai apspriestu ekonomisko situāciju un energoapgādes nākotni. Policija ziņo, ka uzbrukumā tika ievainoti divi cilvēki un izmeklēšana joprojām turpinās.`,
+
+	"lt": `Visi žmonės gimsta laisvi ir lygūs savo orumu ir teisėmis. Jiems suteiktas protas ir sąžinė ir jie turi elgtis vienas kito atžvilgiu kaip broliai. Vyriausybė pirmadienį pranešė, kad naujasis įstatymas įsigalios kitais metais, kai Seimas patvirtins biudžetą. Šalies prezidentas susitiks su regiono ministrais aptarti ekonominės padėties ir energijos tiekimo ateities. Policijos teigimu, per išpuolį buvo sužeisti du žmonės, o tyrimas vis dar tęsiamas.`,
+
+	"tr": `Bütün insanlar hür, haysiyet ve haklar bakımından eşit doğarlar. Akıl ve vicdana sahiptirler ve birbirlerine karşı kardeşlik zihniyeti ile hareket etmelidirler. Hükümet pazartesi günü yeni yasanın meclisin bütçeyi onaylamasının ardından gelecek yıl yürürlüğe gireceğini açıkladı. Ülkenin cumhurbaşkanı ekonomik durumu ve enerji arzının geleceğini görüşmek için bölgedeki bakanlarla bir araya gelecek. Polisin açıklamasına göre saldırıda iki kişi yaralandı ve soruşturma hâlâ devam ediyor.`,
+
+	"id": `Semua orang dilahirkan merdeka dan mempunyai martabat dan hak-hak yang sama. Mereka dikaruniai akal dan hati nurani dan hendaknya bergaul satu sama lain dalam semangat persaudaraan. Pemerintah mengumumkan pada hari Senin bahwa undang-undang yang baru akan mulai berlaku tahun depan, setelah parlemen menyetujui anggaran. Presiden negara itu akan bertemu dengan para menteri dari wilayah tersebut untuk membahas situasi ekonomi dan masa depan pasokan energi. Menurut polisi, dua orang terluka dalam serangan itu dan penyelidikan masih berlangsung.`,
+}
@@ -0,0 +1,63 @@
+package localization
+
+import "testing"
+
+func TestDetectLanguage(t *testing.T) {
+	tests := []struct {
+		text   string
+		want   string
+		wantOK bool
+	}{
+		{"Bundesregierung beschließt neues Klimapaket für die Industrie", "de", true},
+		{"Le gouvernement annonce une nouvelle réforme des retraites", "fr", true},
+		{"Storm hits the northern coast as thousands lose power", "en", true},
+		{"Regeringen presenterar ny budget för nästa år", "sv", true},
+		{"Regjeringen legger fram nytt statsbudsjett i dag", "nb", true},
+		{"Κυβέρνηση ανακοινώνει νέα μέτρα", "el", true},
+		{"Уряд ухвалив бюджет на наступний рік", "uk", true},
+		{"Выборы в этом году пройдут в сентябре", "ru", true},
+		{"政府が新しい予算案を発表しました", "ja", true},
+		{"정부가 새 예산안을 발표했다", "ko", true},
+		{"Live", "", false}, // too short
+		{"Влада усвојила буџет", "", false}, // Cyrillic without a telling letter
+	}
+	for _, tt := range tests {
+		got, ok := DetectLanguage(tt.text)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("DetectLanguage(%q) = %q, %v, want %q, %v", tt.text, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestDetectLanguageWithPrior(t *testing.T) {
+	tests := []struct {
+		text, prior string
+		want        string
+	}{
+		{"Regjeringen legger fram nytt statsbudsjett i dag", "nb", "nb"},
+		{"Regjeringen legger fram nytt statsbudsjett i dag", "", "nb"},
+		// A feed declaring the wrong language does not decide clearly classified items
+		{"Bundesregierung beschließt neues Klimapaket für die Industrie", "en", "de"},
+		{"Le gouvernement annonce une nouvelle réforme des retraites", "en-US", "fr"},
+		{"Regeringen fremlægger nyt finanslovforslag i dag", "nb", "da"},
+		// Text the classifier cannot judge falls back to the prior
+		{"Live", "de", "de"},
+		{"Влада усвојила буџет", "sr", "sr"},
+		{"Live", "", ""},
+	}
+	for _, tt := range tests {
+		if got, _ := DetectLanguageWithPrior(tt.text, tt.prior); got != tt.want {
+			t.Errorf("DetectLanguageWithPrior(%q, %q) = %q, want %q", tt.text, tt.prior, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeLanguage(t *testing.T) {
+	for declared, want := range map[string]string{
+		"fr-BE": "fr", "de_CH": "de", "en-us": "en", " PT ": "pt", "no": "nb", "nn-NO": "nb", "x": "", "": "",
+	} {
+		if got := NormalizeLanguage(declared); got != want {
+			t.Errorf("NormalizeLanguage(%q) = %q, want %q", declared, got, want)
+		}
+	}
+}
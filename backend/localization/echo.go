@@ -0,0 +1,14 @@
+package localization
+
+import "context"
+
+// Echo is a no-op translator that returns every text unchanged.
+// It is meant for local development without a translation provider.
+type Echo struct{}
+
+func (Echo) Name() string { return "Echo" }
+
+// TranslateBatch returns texts as-is.
+func (Echo) TranslateBatch(_ context.Context, texts []string, _, _ string, _ Format) ([]string, error) {
+	return append([]string(nil), texts...), nil
+}
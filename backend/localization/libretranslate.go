@@ -0,0 +1,125 @@
+package localization
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// libreMaxTexts bounds the number of texts sent in a single LibreTranslate request.
+const libreMaxTexts = 50
+
+// LibreTranslate translates through a LibreTranslate-compatible HTTP API,
+// such as a self-hosted instance.
+type LibreTranslate struct {
+	endpoint string
+	apiKey   string
+	client   *http.Client
+}
+
+// NewLibreTranslate creates a translator for the LibreTranslate server at baseURL.
+// The API key is optional and only sent when set.
+func NewLibreTranslate(baseURL, apiKey string) *LibreTranslate {
+	return &LibreTranslate{
+		endpoint: strings.TrimRight(baseURL, "/") + "/translate",
+		apiKey:   apiKey,
+		client:   &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+func (l *LibreTranslate) Name() string { return "LibreTranslate" }
+
+// TranslateBatch translates texts, sending them as a q array per request.
+// When a request fails, the translations of the earlier ones are returned with a *BatchError.
+func (l *LibreTranslate) TranslateBatch(ctx context.Context, texts []string, sourceLang, targetLang string, format Format) ([]string, error) {
+	out := make([]string, 0, len(texts))
+	for _, chunk := range chunkTexts(texts, libreMaxTexts, 1<<20, func(text string) int { return len(text) }) {
+		translated, err := l.translate(ctx, chunk, sourceLang, targetLang, format)
+		if err != nil {
+			sent := len(out)
+			// Error answers are not billed, anything else may have been
+			var apiErr *libreError
+			if !errors.As(err, &apiErr) && !failedToConnect(err) {
+				sent += len(chunk)
+			}
+			return out, &BatchError{Translated: len(out), Sent: sent, Err: err}
+		}
+		out = append(out, translated...)
+	}
+	return out, nil
+}
+
+// translate sends a single translate request.
+func (l *LibreTranslate) translate(ctx context.Context, texts []string, sourceLang, targetLang string, format Format) ([]string, error) {
+	payload := map[string]any{
+		"q":      texts,
+		"source": libreLang(sourceLang),
+		"target": libreLang(targetLang),
+		"format": string(format), // LibreTranslate uses the same "text" and "html" names
+	}
+	if l.apiKey != "" {
+		payload["api_key"] = l.apiKey
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("encoding request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", l.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		msg, _ := io.ReadAll(resp.Body)
+		return nil, &libreError{StatusCode: resp.StatusCode, Body: string(msg)}
+	}
+
+	var result struct {
+		TranslatedText []string `json:"translatedText"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode LibreTranslate response: %w", err)
+	}
+	if len(result.TranslatedText) != len(texts) {
+		return nil, fmt.Errorf("libretranslate returned %d translations for %d texts", len(result.TranslatedText), len(texts))
+	}
+	return result.TranslatedText, nil
+}
+
+// libreError is a non-200 response from a LibreTranslate server.
+type libreError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *libreError) Error() string {
+	return fmt.Sprintf("libretranslate error %d: %s", e.StatusCode, e.Body)
+}
+
+// libreLang converts a DeepL-style code ("PT-BR", "ZH", "NB") into the lower-case
+// ISO 639-1 code LibreTranslate expects. An unknown language becomes "auto", which
+// has LibreTranslate detect it.
+func libreLang(lang string) string {
+	base := strings.ToLower(strings.TrimSpace(strings.Split(lang, "-")[0]))
+	switch base {
+	case "":
+		return "auto"
+	case "nb":
+		return "no"
+	}
+	return base
+}
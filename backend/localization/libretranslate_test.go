@@ -0,0 +1,39 @@
+package localization
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLibreTranslateLanguages(t *testing.T) {
+	var got []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("decoding request: %v", err)
+		}
+		got = append(got, payload)
+		json.NewEncoder(w).Encode(map[string]any{"translatedText": payload["q"]})
+	}))
+	defer server.Close()
+
+	l := NewLibreTranslate(server.URL, "")
+	for _, source := range []string{"", "PT-BR", "NB"} {
+		if _, err := l.TranslateBatch(context.Background(), []string{"text"}, source, "EN-GB", FormatText); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := [][2]string{{"auto", "en"}, {"pt", "en"}, {"no", "en"}}
+	if len(got) != len(want) {
+		t.Fatalf("got %d requests, want %d", len(got), len(want))
+	}
+	for i, payload := range got {
+		if payload["source"] != want[i][0] || payload["target"] != want[i][1] {
+			t.Errorf("request %d: source %v, target %v, want %q, %q", i, payload["source"], payload["target"], want[i][0], want[i][1])
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package localization
+
+import (
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+)
+
+// maxSegmentBytes is the size above which a text is split into sentence-sized segments.
+// It keeps single texts well within provider limits (LibreTranslate's default is
+// 5000 characters) and below DeepL's per-request size.
+const maxSegmentBytes = 4000
+
+// splittableTags are the block elements a segment may start or end inside. Their tags are
+// closed at the end of a segment and reopened at the start of the next one; inline
+// elements such as links are never split.
+var splittableTags = map[string]bool{
+	"p": true, "div": true, "blockquote": true, "ul": true, "ol": true, "li": true,
+}
+
+// voidTags never have an end tag.
+var voidTags = map[string]bool{
+	"br": true, "hr": true, "img": true, "wbr": true, "input": true, "source": true,
+}
+
+// segment is a piece of a long text. Prefix and suffix are the tags reopened and closed
+// to keep the piece well-formed; they are removed again when the pieces are joined.
+type segment struct {
+	text   string
+	prefix string
+	suffix string
+}
+
+// cut is a position where a text may be split, with the raw start tags open there.
+type cut struct {
+	pos  int
+	open []openTag
+}
+
+// openTag is an element that is open at a cut.
+type openTag struct {
+	name string
+	raw  string
+}
+
+// splitSegments splits a text longer than maxBytes at sentence ends and block boundaries
+// into pieces of at most maxBytes where possible. A single sentence longer than maxBytes
+// stays in one piece. Shorter texts are returned as a single segment.
+func splitSegments(text string, format Format, maxBytes int) []segment {
+	if len(text) <= maxBytes {
+		return []segment{{text: text}}
+	}
+
+	var cuts []cut
+	if format == FormatHTML {
+		cuts = htmlCuts(text)
+	} else {
+		for _, pos := range sentenceEnds(text) {
+			cuts = append(cuts, cut{pos: pos})
+		}
+	}
+	if len(cuts) == 0 || cuts[len(cuts)-1].pos < len(text) {
+		cuts = append(cuts, cut{pos: len(text)})
+	}
+
+	var (
+		segments []segment
+		start    cut
+		last     = start
+	)
+	for _, c := range cuts {
+		if c.pos-start.pos > maxBytes && last.pos > start.pos {
+			segments = append(segments, newSegment(text[start.pos:last.pos], start.open, last.open))
+			start = last
+		}
+		last = c
+	}
+	return append(segments, newSegment(text[start.pos:], start.open, nil))
+}
+
+// newSegment wraps a piece of text in the tags open at its start and end.
+func newSegment(body string, startOpen, endOpen []openTag) segment {
+	var prefix, suffix strings.Builder
+	for _, t := range startOpen {
+		prefix.WriteString(t.raw)
+	}
+	for i := len(endOpen) - 1; i >= 0; i-- {
+		suffix.WriteString("</" + endOpen[i].name + ">")
+	}
+	return segment{
+		text:   prefix.String() + strings.TrimSpace(body) + suffix.String(),
+		prefix: prefix.String(),
+		suffix: suffix.String(),
+	}
+}
+
+// joinSegments reassembles the translations of a text's segments, removing the tags that
+// were only added to keep the segments well-formed. Segments are separated by a space,
+// except after full-width punctuation.
+func joinSegments(segments []segment, translated []string) string {
+	if len(segments) == 1 && segments[0].prefix == "" && segments[0].suffix == "" {
+		return translated[0]
+	}
+
+	var b strings.Builder
+	for i, text := range translated {
+		text = strings.TrimSpace(text)
+		text = strings.TrimPrefix(text, segments[i].prefix)
+		text = strings.TrimSuffix(text, segments[i].suffix)
+		if last, _ := utf8.DecodeLastRuneInString(b.String()); i > 0 && !isFullWidthStop(last) {
+			b.WriteString(" ")
+		}
+		b.WriteString(strings.TrimSpace(text))
+	}
+	return b.String()
+}
+
+// htmlCuts returns the positions in an HTML fragment where it can be split: sentence ends
+// and block boundaries where only splittable elements are open.
+func htmlCuts(fragment string) []cut {
+	var (
+		cuts  []cut
+		stack []openTag
+		pos   int
+	)
+	splittable := func() bool {
+		for _, t := range stack {
+			if !splittableTags[t.name] {
+				return false
+			}
+		}
+		return true
+	}
+	snapshot := func(at int) cut {
+		return cut{pos: at, open: append([]openTag(nil), stack...)}
+	}
+
+	z := html.NewTokenizer(strings.NewReader(fragment))
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			if z.Err() != io.EOF {
+				// Unparsable markup: only split where it is known to be safe
+				return nil
+			}
+			return cuts
+		}
+		raw := string(z.Raw())
+		start := pos
+		pos += len(raw)
+
+		switch tt {
+		case html.TextToken:
+			if splittable() {
+				for _, end := range sentenceEnds(raw) {
+					cuts = append(cuts, snapshot(start+end))
+				}
+			}
+		case html.StartTagToken:
+			name, _ := z.TagName()
+			if voidTags[string(name)] {
+				if splittable() {
+					cuts = append(cuts, snapshot(pos))
+				}
+				continue
+			}
+			stack = append(stack, openTag{name: string(name), raw: raw})
+		case html.SelfClosingTagToken:
+			if splittable() {
+				cuts = append(cuts, snapshot(pos))
+			}
+		case html.EndTagToken:
+			name, _ := z.TagName()
+			for i := len(stack) - 1; i >= 0; i-- {
+				if stack[i].name == string(name) {
+					stack = stack[:i]
+					break
+				}
+			}
+			if splittableTags[string(name)] && splittable() {
+				cuts = append(cuts, snapshot(pos))
+			}
+		}
+	}
+}
+
+// sentenceEnds returns the byte offsets right after sentence-ending punctuation that is
+// followed by whitespace, or after full-width punctuation, which needs none.
+func sentenceEnds(text string) []int {
+	var ends []int
+	for i, r := range text {
+		size := utf8.RuneLen(r)
+		switch {
+		case isFullWidthStop(r):
+			ends = append(ends, i+size)
+		case r == '.', r == '!', r == '?', r == '…':
+			next, _ := utf8.DecodeRuneInString(text[i+size:])
+			if unicode.IsSpace(next) {
+				ends = append(ends, i+size)
+			}
+		}
+	}
+	return ends
+}
+
+// isFullWidthStop reports whether r is full-width sentence punctuation, which is never
+// followed by a space.
+func isFullWidthStop(r rune) bool {
+	return r == '。' || r == '！' || r == '？'
+}
@@ -0,0 +1,83 @@
+package localization
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestSplitJoinSegmentsRoundTrip(t *testing.T) {
+	sentence := "The storm reached the coast this morning. "
+	tests := []struct {
+		name         string
+		text         string
+		format       Format
+		maxBytes     int
+		wantSegments int
+	}{
+		{"short text stays whole", "One sentence only.", FormatText, 100, 1},
+		{"plain sentences", strings.Repeat(sentence, 10), FormatText, 100, 5},
+		{"full-width punctuation", strings.Repeat("沿岸に嵐が到達しました。", 10), FormatText, 100, 5},
+		{"long sentence stays whole", strings.Repeat("word ", 50) + "end.", FormatText, 100, 1},
+		{"paragraphs", strings.Repeat("<p>"+sentence+sentence+"</p>", 4), FormatHTML, 100, 4},
+		{"sentences inside one paragraph", "<p>" + strings.Repeat(sentence, 6) + "</p>", FormatHTML, 100, 3},
+		{"nested lists", "<ul>" + strings.Repeat("<li>"+sentence+"</li>", 6) + "</ul>", FormatHTML, 120, 3},
+		{"links are never split", "<p>" + strings.Repeat(`<a href="https://example.com">`+sentence+sentence+"</a> ", 3) + "</p>", FormatHTML, 100, 1},
+		{"line breaks", strings.Repeat(sentence+"<br>", 6), FormatHTML, 100, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			segments := splitSegments(tt.text, tt.format, tt.maxBytes)
+			if len(segments) != tt.wantSegments {
+				t.Errorf("got %d segments, want %d", len(segments), tt.wantSegments)
+			}
+
+			texts := make([]string, len(segments))
+			for i, s := range segments {
+				if !strings.HasPrefix(s.text, s.prefix) || !strings.HasSuffix(s.text, s.suffix) {
+					t.Errorf("segment %d %q lacks its prefix %q or suffix %q", i, s.text, s.prefix, s.suffix)
+				}
+				if tt.format == FormatHTML && !balanced(s.text) {
+					t.Errorf("segment %d is not well-formed: %q", i, s.text)
+				}
+				// Translators tend to pad their output with whitespace
+				texts[i] = " " + s.text + "\n"
+			}
+
+			if got, want := normalizeSpacing(joinSegments(segments, texts)), normalizeSpacing(tt.text); got != want {
+				t.Errorf("round trip changed the text:\n got %q\nwant %q", got, want)
+			}
+		})
+	}
+}
+
+// tagPattern matches the start and end tags of non-void elements.
+var tagPattern = regexp.MustCompile(`<(/?)([a-z]+)[^>]*>`)
+
+// balanced reports whether every start tag in fragment is closed in the right order.
+func balanced(fragment string) bool {
+	var stack []string
+	for _, m := range tagPattern.FindAllStringSubmatch(fragment, -1) {
+		name := m[2]
+		if voidTags[name] {
+			continue
+		}
+		if m[1] == "" {
+			stack = append(stack, name)
+			continue
+		}
+		if len(stack) == 0 || stack[len(stack)-1] != name {
+			return false
+		}
+		stack = stack[:len(stack)-1]
+	}
+	return len(stack) == 0
+}
+
+// normalizeSpacing collapses whitespace and drops it next to tags, where joining
+// segments may add or remove it without changing the rendered text.
+func normalizeSpacing(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	text = strings.ReplaceAll(text, "> ", ">")
+	return strings.ReplaceAll(text, " <", "<")
+}
@@ -0,0 +1,80 @@
+package localization
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultTargetLang is used when a request names no supported target language.
+const DefaultTargetLang = "EN"
+
+// targetLangs lists the target languages DeepL accepts, including regional variants.
+var targetLangs = map[string]bool{
+	"AR": true, "BG": true, "CS": true, "DA": true, "DE": true, "EL": true,
+	"EN": true, "EN-GB": true, "EN-US": true, "ES": true, "ET": true, "FI": true,
+	"FR": true, "HU": true, "ID": true, "IT": true, "JA": true, "KO": true,
+	"LT": true, "LV": true, "NB": true, "NL": true, "PL": true, "PT": true,
+	"PT-BR": true, "PT-PT": true, "RO": true, "RU": true, "SK": true, "SL": true,
+	"SV": true, "TR": true, "UK": true, "ZH": true,
+}
+
+// IsSupportedSource reports whether a language (any case, with or without region)
+// can be used as a translation source.
+func IsSupportedSource(lang string) bool {
+	return targetLangs[baseLang(lang)]
+}
+
+// NormalizeTargetLang maps a language tag such as "de", "pt-br" or "en-AU" to a supported
+// target language code. Unknown regional variants fall back to their base language.
+func NormalizeTargetLang(tag string) (string, bool) {
+	code := strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(tag), "_", "-"))
+	if code == "" {
+		return "", false
+	}
+	if targetLangs[code] {
+		return code, true
+	}
+
+	base := baseLang(code)
+	if base == "NO" || base == "NN" {
+		base = "NB"
+	}
+	if targetLangs[base] {
+		return base, true
+	}
+	return "", false
+}
+
+// TargetFromAcceptLanguage returns the preferred supported language of an
+// Accept-Language header, honoring q-values.
+func TargetFromAcceptLanguage(header string) (string, bool) {
+	type candidate struct {
+		tag string
+		q   float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		c := candidate{tag: strings.TrimSpace(fields[0]), q: 1}
+		for _, param := range fields[1:] {
+			if v, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+				if q, err := strconv.ParseFloat(v, 64); err == nil {
+					c.q = q
+				}
+			}
+		}
+		if c.tag != "" && c.tag != "*" && c.q > 0 {
+			candidates = append(candidates, c)
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+	for _, c := range candidates {
+		if lang, ok := NormalizeTargetLang(c.tag); ok {
+			return lang, true
+		}
+	}
+	return "", false
+}
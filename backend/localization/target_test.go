@@ -0,0 +1,45 @@
+package localization
+
+import "testing"
+
+func TestNormalizeTargetLang(t *testing.T) {
+	for tag, want := range map[string]string{
+		"de":      "DE",
+		"pt-br":   "PT-BR",
+		"pt_PT":   "PT-PT",
+		"en-AU":   "EN",
+		" fr-CA":  "FR",
+		"no":      "NB",
+		"nn-NO":   "NB",
+		"zh-Hant": "ZH",
+		"xx":      "",
+		"":        "",
+	} {
+		got, ok := NormalizeTargetLang(tag)
+		if got != want || ok != (want != "") {
+			t.Errorf("NormalizeTargetLang(%q) = %q, %v, want %q", tag, got, ok, want)
+		}
+	}
+}
+
+func TestTargetFromAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"de-DE,de;q=0.9,en;q=0.8", "DE"},
+		{"en;q=0.5, fr;q=0.9", "FR"},
+		{"x-klingon, it;q=0.3", "IT"},                // unsupported tags are skipped
+		{"ja;q=0, ko;q=0.1", "KO"},                   // q=0 means not acceptable
+		{"*, es;q=0.2", "ES"},                        // the wildcard names no language
+		{"pt-BR;q=0.8, pt;q=0.8, en;q=0.7", "PT-BR"}, // ties keep header order
+		{"", ""},
+		{"tlh", ""},
+	}
+	for _, tt := range tests {
+		got, ok := TargetFromAcceptLanguage(tt.header)
+		if got != tt.want || ok != (tt.want != "") {
+			t.Errorf("TargetFromAcceptLanguage(%q) = %q, %v, want %q", tt.header, got, ok, tt.want)
+		}
+	}
+}
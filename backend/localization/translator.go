@@ -0,0 +1,225 @@
+package localization
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+)
+
+// Translator translates short texts such as headlines and descriptions.
+type Translator interface {
+	// Name identifies the provider in logs and admin output.
+	Name() string
+	// TranslateBatch translates texts that share a source and target language (DeepL-style
+	// codes such as "DE" or "PT-BR") and a format. The result has the same length and order as texts.
+	TranslateBatch(ctx context.Context, texts []string, sourceLang, targetLang string, format Format) ([]string, error)
+}
+
+// BatchError is returned by a provider whose batch failed after being split into several
+// requests. The translations of the first Translated texts are returned along with it, so
+// they can be kept; the first Sent texts reached the provider, which may have billed them.
+type BatchError struct {
+	Translated int
+	Sent       int
+	Err        error
+}
+
+func (e *BatchError) Error() string { return e.Err.Error() }
+
+func (e *BatchError) Unwrap() error { return e.Err }
+
+// Format tells the provider how to treat markup in the texts of a batch.
+type Format string
+
+const (
+	FormatText Format = "text" // plain text; callers strip markup first
+	FormatHTML Format = "html" // HTML fragments; tags are kept and only the text is translated
+)
+
+// Request is a single text queued for translation.
+type Request struct {
+	Text       string
+	SourceLang string
+	TargetLang string
+	Format     Format // defaults to FormatText
+}
+
+// Result is the outcome of a single Request.
+type Result struct {
+	Text string
+	Err  error
+}
+
+// Translate translates a single plain text.
+func Translate(ctx context.Context, t Translator, text, sourceLang, targetLang string) (string, error) {
+	out, err := t.TranslateBatch(ctx, []string{text}, sourceLang, targetLang, FormatText)
+	if err != nil {
+		return "", err
+	}
+	return out[0], nil
+}
+
+// TranslateAll translates requests with mixed languages and formats using one batch per
+// language pair and format. Texts longer than maxSegmentBytes are translated in
+// sentence-sized segments and joined back together.
+// Results are returned in request order; a failed batch only fails its own requests.
+func TranslateAll(ctx context.Context, t Translator, reqs []Request) []Result {
+	results := make([]Result, len(reqs))
+
+	type group struct {
+		source, target string
+		format         Format
+	}
+	type slot struct{ req, seg int }
+
+	segments := make([][]segment, len(reqs))
+	translated := make([][]string, len(reqs))
+	groups := make(map[group][]slot) // language pair and format -> request segments
+	var order []group
+	for i, r := range reqs {
+		format := r.Format
+		if format == "" {
+			format = FormatText
+		}
+		segments[i] = splitSegments(r.Text, format, maxSegmentBytes)
+		translated[i] = make([]string, len(segments[i]))
+
+		key := group{r.SourceLang, r.TargetLang, format}
+		if _, ok := groups[key]; !ok {
+			order = append(order, key)
+		}
+		for j := range segments[i] {
+			groups[key] = append(groups[key], slot{i, j})
+		}
+	}
+
+	for _, key := range order {
+		slots := groups[key]
+		texts := make([]string, len(slots))
+		for j, s := range slots {
+			texts[j] = segments[s.req][s.seg].text
+		}
+
+		out, err := t.TranslateBatch(ctx, texts, key.source, key.target, key.format)
+		for j, s := range slots {
+			if err != nil {
+				results[s.req].Err = err
+				continue
+			}
+			translated[s.req][s.seg] = out[j]
+		}
+	}
+
+	for i := range reqs {
+		if results[i].Err == nil {
+			results[i].Text = joinSegments(segments[i], translated[i])
+		}
+	}
+	return results
+}
+
+// chunkTexts splits texts into consecutive batches of at most maxTexts entries and
+// at most maxBytes, so every batch fits into a single provider request. sizeOf returns
+// the bytes a text takes up in the request. A text larger than maxBytes gets a batch of its own.
+func chunkTexts(texts []string, maxTexts, maxBytes int, sizeOf func(string) int) [][]string {
+	var (
+		chunks [][]string
+		start  int
+		size   int
+	)
+	for i, text := range texts {
+		textSize := sizeOf(text)
+		if i > start && (i-start >= maxTexts || size+textSize > maxBytes) {
+			chunks = append(chunks, texts[start:i])
+			start, size = i, 0
+		}
+		size += textSize
+	}
+	if start < len(texts) {
+		chunks = append(chunks, texts[start:])
+	}
+	return chunks
+}
+
+// Usage is a provider's character consumption for the current billing period.
+type Usage struct {
+	CharacterCount int64 `json:"character_count"`
+	CharacterLimit int64 `json:"character_limit"`
+}
+
+// UsageReporter is implemented by translators that can report their provider-side usage.
+type UsageReporter interface {
+	Usage(ctx context.Context) (Usage, error)
+}
+
+// ErrUsageUnsupported is returned by ProviderUsage for providers without usage reporting.
+var ErrUsageUnsupported = errors.New("translator does not report usage")
+
+// NewTranslatorFromEnv builds the translator selected by TRANSLATOR:
+//   - "deepl" (default when DEEPL_API_KEY is set) uses DEEPL_API_KEY and the optional DEEPL_API_URL
+//   - "libretranslate" uses LIBRETRANSLATE_URL and the optional LIBRETRANSLATE_API_KEY
+//   - "echo" (default otherwise) returns texts unchanged, for local development
+//
+// Billing providers are wrapped in a character budget (see BudgetConfigFromEnv),
+// and every provider in the translation cache.
+func NewTranslatorFromEnv() (Translator, error) {
+	provider := strings.ToLower(os.Getenv("TRANSLATOR"))
+	if provider == "" {
+		provider = "echo"
+		if os.Getenv("DEEPL_API_KEY") != "" {
+			provider = "deepl"
+		}
+	}
+
+	var t Translator
+	switch provider {
+	case "deepl":
+		key := os.Getenv("DEEPL_API_KEY")
+		if key == "" {
+			return nil, errors.New("DEEPL_API_KEY is not set")
+		}
+		t = WithBudget(NewDeepL(key, os.Getenv("DEEPL_API_URL")), BudgetConfigFromEnv(deepLDefaultBudget(key)))
+	case "libretranslate":
+		endpoint := os.Getenv("LIBRETRANSLATE_URL")
+		if endpoint == "" {
+			return nil, errors.New("LIBRETRANSLATE_URL is not set")
+		}
+		t = WithBudget(NewLibreTranslate(endpoint, os.Getenv("LIBRETRANSLATE_API_KEY")), BudgetConfigFromEnv(0))
+	case "echo", "none":
+		t = Echo{}
+	default:
+		return nil, fmt.Errorf("unknown TRANSLATOR %q", provider)
+	}
+
+	log.Printf("🌐 Using %s translator", t.Name())
+	return WithCache(t), nil
+}
+
+// ProviderUsage returns the usage reported by the provider behind t, looking through wrappers.
+func ProviderUsage(ctx context.Context, t Translator) (Usage, error) {
+	for t != nil {
+		if reporter, ok := t.(UsageReporter); ok {
+			return reporter.Usage(ctx)
+		}
+		wrapper, ok := t.(interface{ Unwrap() Translator })
+		if !ok {
+			break
+		}
+		t = wrapper.Unwrap()
+	}
+	return Usage{}, ErrUsageUnsupported
+}
+
+// SameLanguage reports whether two language codes share their base language,
+// e.g. "EN" and "EN-GB", in which case there is nothing to translate.
+func SameLanguage(a, b string) bool {
+	return baseLang(a) == baseLang(b)
+}
+
+// baseLang returns the upper-case base language of a code such as "pt-BR".
+func baseLang(lang string) string {
+	return strings.ToUpper(strings.Split(lang, "-")[0])
+}
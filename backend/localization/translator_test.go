@@ -0,0 +1,87 @@
+package localization
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestNewTranslatorFromEnv(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		provider string // empty when an error is expected
+	}{
+		{"nothing configured", nil, "Echo"},
+		{"DeepL key alone", map[string]string{"DEEPL_API_KEY": "key:fx"}, "DeepL"},
+		{"explicit echo wins over a key", map[string]string{"TRANSLATOR": "echo", "DEEPL_API_KEY": "key:fx"}, "Echo"},
+		{"LibreTranslate", map[string]string{"TRANSLATOR": "LibreTranslate", "LIBRETRANSLATE_URL": "http://localhost:5000"}, "LibreTranslate"},
+		{"DeepL without key", map[string]string{"TRANSLATOR": "deepl"}, ""},
+		{"LibreTranslate without URL", map[string]string{"TRANSLATOR": "libretranslate"}, ""},
+		{"unknown provider", map[string]string{"TRANSLATOR": "babelfish"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{"TRANSLATOR", "DEEPL_API_KEY", "DEEPL_API_URL", "LIBRETRANSLATE_URL", "LIBRETRANSLATE_API_KEY"} {
+				t.Setenv(key, tt.env[key])
+			}
+			tr, err := NewTranslatorFromEnv()
+			if tt.provider == "" {
+				if err == nil {
+					t.Errorf("got %s, want an error", tr.Name())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tr.Name() != tt.provider {
+				t.Errorf("provider %s, want %s", tr.Name(), tt.provider)
+			}
+		})
+	}
+}
+
+// pairTranslator tags texts with their language pair and fails batches into failTarget.
+type pairTranslator struct {
+	batches    int
+	failTarget string
+}
+
+func (p *pairTranslator) Name() string { return "pairs" }
+
+func (p *pairTranslator) TranslateBatch(ctx context.Context, texts []string, sourceLang, targetLang string, format Format) ([]string, error) {
+	p.batches++
+	if targetLang == p.failTarget {
+		return nil, errors.New("unsupported target")
+	}
+	out := make([]string, len(texts))
+	for i, text := range texts {
+		out[i] = sourceLang + ">" + targetLang + ":" + text
+	}
+	return out, nil
+}
+
+func TestTranslateAllGroupsByLanguagePair(t *testing.T) {
+	p := &pairTranslator{failTarget: "JA"}
+	results := TranslateAll(context.Background(), p, []Request{
+		{Text: "eins", SourceLang: "DE", TargetLang: "EN"},
+		{Text: "un", SourceLang: "FR", TargetLang: "EN"},
+		{Text: "zwei", SourceLang: "DE", TargetLang: "EN"},
+		{Text: "drei", SourceLang: "DE", TargetLang: "JA"},
+		{Text: "deux", SourceLang: "FR", TargetLang: "EN"},
+	})
+
+	want := []string{"DE>EN:eins", "FR>EN:un", "DE>EN:zwei", "", "FR>EN:deux"}
+	for i, r := range results {
+		if r.Text != want[i] {
+			t.Errorf("result %d = %q, want %q", i, r.Text, want[i])
+		}
+		if failed := r.Err != nil; failed != (i == 3) {
+			t.Errorf("result %d: err = %v", i, r.Err)
+		}
+	}
+	if p.batches != 3 {
+		t.Errorf("sent %d batches, want one per language pair", p.batches)
+	}
+}
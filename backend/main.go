@@ -1,13 +1,21 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/frogfromlake/Orbitalone/backend/feeds"
+	"github.com/frogfromlake/Orbitalone/backend/ingest"
+	"github.com/frogfromlake/Orbitalone/backend/localization"
 	"github.com/frogfromlake/Orbitalone/backend/routes"
+	"github.com/frogfromlake/Orbitalone/backend/utils"
 	"github.com/joho/godotenv"
 )
 
@@ -40,16 +48,61 @@ func main() {
 	}
 	log.Printf("✅ Database sucessfully initialized")
 
+	// Select the translation provider (DeepL, LibreTranslate or echo)
+	translator, err := localization.NewTranslatorFromEnv()
+	if err != nil {
+		log.Fatalf("❌ Translator init failed: %v", err)
+	}
+
+	// Stop everything on SIGINT/SIGTERM (Fly.io sends SIGTERM on auto-stop)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Save translation cache hits in the background
+	localization.StartCaches(ctx, translator)
+
+	// Fetch article pages for stub descriptions apart from the feed polls
+	extractor := utils.NewExtractionWorker(utils.ExtractionWorkersFromEnv())
+	extractor.Start(ctx)
+
+	// Start background feed ingestion
+	poller := ingest.NewPoller(ingest.ConfigFromEnv(), extractor)
+	poller.Start(ctx)
+
+	// Make articles ingested before search existed searchable
+	go utils.BackfillSearchIndex(ctx)
+
+	// Translate news articles in the background
+	worker := utils.NewTranslationWorker(utils.TranslationWorkersFromEnv())
+	worker.Start(ctx)
+
 	// Set up routes and start the server
 	mux := http.NewServeMux()
-	routes.Register(mux, env)
+	routes.Register(mux, env, translator, worker)
 
 	addr := fmt.Sprintf("0.0.0.0:%s", port)
-	log.Printf("✅ Server running at http://%s\n", addr)
+	server := &http.Server{Addr: addr, Handler: mux}
+
+	go func() {
+		log.Printf("✅ Server running at http://%s\n", addr)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("❌ ListenAndServe failed: %v", err)
+		}
+	}()
+
+	<-ctx.Done()
+	log.Println("👋 Shutting down...")
 
-	if err := http.ListenAndServe(addr, mux); err != nil {
-		log.Fatalf("❌ ListenAndServe failed: %v", err)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("⚠️  Server shutdown error: %v", err)
 	}
+	worker.Stop()
+	poller.Stop()
+	extractor.Stop()
+	localization.StopCaches(translator)
+	localization.SaveHits(translator)
 }
 
 // getEnv returns an environment variable or a fallback if unset.
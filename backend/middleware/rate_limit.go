@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/patrickmn/go-cache"
+)
+
+// RateLimiter grants every client IP a fixed allowance of units (requests, characters, ...)
+// per time window. The window starts with a client's first request.
+type RateLimiter struct {
+	limit  int
+	window time.Duration
+
+	mu   sync.Mutex
+	used *cache.Cache // client IP -> units spent in the current window
+}
+
+// NewRateLimiter creates a limiter allowing limit units per client and window.
+// A limit of 0 or less disables limiting.
+func NewRateLimiter(limit int, window time.Duration) *RateLimiter {
+	return &RateLimiter{
+		limit:  limit,
+		window: window,
+		used:   cache.New(window, window),
+	}
+}
+
+// Allow spends n units of the client's allowance. When they don't fit, nothing is spent
+// and it returns false with the time until the client's window ends.
+func (l *RateLimiter) Allow(r *http.Request, n int) (bool, time.Duration) {
+	if l.limit <= 0 {
+		return true, 0
+	}
+	ip := rateLimitIP(r)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	used, expires, found := l.used.GetWithExpiration(ip)
+	if !found {
+		if n > l.limit {
+			return false, l.window
+		}
+		l.used.Set(ip, n, l.window)
+		return true, 0
+	}
+	if used.(int)+n > l.limit {
+		return false, time.Until(expires)
+	}
+	l.used.Set(ip, used.(int)+n, time.Until(expires))
+	return true, 0
+}
+
+// rateLimitIP identifies the client for rate limiting. Unlike getClientIP it never trusts
+// the client-supplied start of X-Forwarded-For: it takes Fly.io's Fly-Client-IP, then the
+// address appended by the nearest proxy, then the connection's remote address.
+func rateLimitIP(r *http.Request) string {
+	if ip := r.Header.Get("Fly-Client-IP"); ip != "" {
+		return ip
+	}
+	if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+		parts := strings.Split(forwarded, ",")
+		return strings.TrimSpace(parts[len(parts)-1])
+	}
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func requestFrom(remoteAddr string, headers ...string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/api/translate", nil)
+	r.RemoteAddr = remoteAddr
+	for i := 0; i+1 < len(headers); i += 2 {
+		r.Header.Set(headers[i], headers[i+1])
+	}
+	return r
+}
+
+func TestRateLimiterAllowance(t *testing.T) {
+	l := NewRateLimiter(100, time.Hour)
+	alice := requestFrom("203.0.113.7:4711")
+	bob := requestFrom("198.51.100.2:80")
+
+	steps := []struct {
+		r    *http.Request
+		n    int
+		want bool
+	}{
+		{alice, 60, true},
+		{alice, 50, false}, // 110 > 100, nothing spent
+		{alice, 40, true},  // exactly 100
+		{alice, 1, false},
+		{bob, 100, true}, // own allowance
+		{bob, 101, false},
+	}
+	for i, s := range steps {
+		ok, wait := l.Allow(s.r, s.n)
+		if ok != s.want {
+			t.Fatalf("step %d: Allow(%s, %d) = %v, want %v", i, s.r.RemoteAddr, s.n, ok, s.want)
+		}
+		if ok && wait != 0 || !ok && (wait <= 0 || wait > time.Hour) {
+			t.Errorf("step %d: wait %v", i, wait)
+		}
+	}
+
+	if ok, wait := NewRateLimiter(10, time.Minute).Allow(alice, 11); ok || wait != time.Minute {
+		t.Errorf("oversized first request: %v, %v; want rejected for the whole window", ok, wait)
+	}
+}
+
+func TestRateLimiterWindowExpires(t *testing.T) {
+	l := NewRateLimiter(5, 50*time.Millisecond)
+	r := requestFrom("203.0.113.7:4711")
+
+	if ok, _ := l.Allow(r, 5); !ok {
+		t.Fatal("first request rejected")
+	}
+	if ok, _ := l.Allow(r, 1); ok {
+		t.Fatal("allowance not spent")
+	}
+	time.Sleep(60 * time.Millisecond)
+	if ok, _ := l.Allow(r, 5); !ok {
+		t.Error("allowance not renewed after the window")
+	}
+}
+
+func TestRateLimiterDisabled(t *testing.T) {
+	for _, limit := range []int{0, -1} {
+		if ok, _ := NewRateLimiter(limit, time.Hour).Allow(requestFrom("203.0.113.7:4711"), 1_000_000); !ok {
+			t.Errorf("limit %d rejected a request", limit)
+		}
+	}
+}
+
+func TestRateLimitIP(t *testing.T) {
+	tests := []struct {
+		name string
+		r    *http.Request
+		want string
+	}{
+		{"remote address", requestFrom("203.0.113.7:4711"), "203.0.113.7"},
+		{"remote address without port", requestFrom("203.0.113.7"), "203.0.113.7"},
+		{"Fly-Client-IP first", requestFrom("10.0.0.1:80", "Fly-Client-IP", "198.51.100.2", "X-Forwarded-For", "1.2.3.4"), "198.51.100.2"},
+		{"nearest proxy entry", requestFrom("10.0.0.1:80", "X-Forwarded-For", "1.2.3.4, 198.51.100.2"), "198.51.100.2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rateLimitIP(tt.r); got != tt.want {
+				t.Errorf("rateLimitIP = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -5,14 +5,30 @@ import (
 	"net/http"
 
 	"github.com/frogfromlake/Orbitalone/backend/handlers"
+	"github.com/frogfromlake/Orbitalone/backend/localization"
 	"github.com/frogfromlake/Orbitalone/backend/middleware"
+	"github.com/frogfromlake/Orbitalone/backend/utils"
 )
 
 // Register mounts all application routes onto the provided mux.
 // This includes public and admin endpoints, with environment-based toggles.
-func Register(mux *http.ServeMux, env string) {
+// The translator is shared by every endpoint that translates or reports usage;
+// the worker translates news articles in the background.
+func Register(mux *http.ServeMux, env string, translator localization.Translator, worker *utils.TranslationWorker) {
 	// Public API endpoint for country-level news
-	mux.Handle("/api/news", http.HandlerFunc(handlers.NewsHandler))
+	mux.Handle("/api/news", handlers.NewsHandler(translator, worker))
+
+	// Translation progress of articles returned as pending by /api/news
+	mux.Handle("/api/news/translations", handlers.NewsTranslationsHandler(worker))
+
+	// On-demand translation of single articles or texts
+	mux.Handle("/api/translate", handlers.TranslateHandler(translator))
+
+	// Full-text search over original and translated articles
+	mux.Handle("/api/search/news", http.HandlerFunc(handlers.SearchNewsHandler))
+
+	// News availability per country, for shading the globe
+	mux.Handle("/api/coverage", http.HandlerFunc(handlers.CoverageHandler))
 
 	// DEV-only admin tools (disabled in production)
 	if env != "production" {
@@ -38,8 +54,20 @@ func Register(mux *http.ServeMux, env string) {
 			middleware.AdminAuth(http.HandlerFunc(handlers.AdminExportFeedsHandler)),
 		))
 
+		mux.Handle("/admin/feeds/health", middleware.CORSHandler(
+			middleware.AdminAuth(http.HandlerFunc(handlers.AdminFeedHealthHandler)),
+		))
+
+		mux.Handle("/admin/regions", middleware.CORSHandler(
+			middleware.AdminAuth(http.HandlerFunc(handlers.AdminRegionsHandler)),
+		))
+
 		mux.Handle("/admin/deepl/usage", middleware.CORSHandler(
-			middleware.AdminAuth(http.HandlerFunc(handlers.GetDeepLUsage)),
+			middleware.AdminAuth(handlers.GetDeepLUsage(translator)),
+		))
+
+		mux.Handle("/admin/translations", middleware.CORSHandler(
+			middleware.AdminAuth(handlers.AdminTranslationsHandler(translator)),
 		))
 
 		mux.Handle("/admin/ping", middleware.CORSHandler(
@@ -0,0 +1,25 @@
+package utils
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+const (
+	retryBaseDelay = 5 * time.Minute
+	retryMaxDelay  = 24 * time.Hour
+	retryJitter    = 0.2 // ±20% so failing feeds don't retry in lockstep
+)
+
+// retryDelay returns how long to wait before retrying a feed after the given number
+// of consecutive failures: 5m, 10m, 20m, ... capped at 24h, with random jitter.
+func retryDelay(failures int) time.Duration {
+	delay := retryBaseDelay
+	for i := 1; i < failures && delay < retryMaxDelay; i++ {
+		delay *= 2
+	}
+	delay = min(delay, retryMaxDelay)
+
+	jitter := 1 + retryJitter*(2*rand.Float64()-1)
+	return time.Duration(float64(delay) * jitter)
+}
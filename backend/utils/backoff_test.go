@@ -0,0 +1,92 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/frogfromlake/Orbitalone/backend/feeds"
+)
+
+func TestRetryDelay(t *testing.T) {
+	tests := []struct {
+		failures int
+		base     time.Duration
+	}{
+		{1, 5 * time.Minute},
+		{2, 10 * time.Minute},
+		{3, 20 * time.Minute},
+		{6, 160 * time.Minute},
+		{10, retryMaxDelay},
+		{1000, retryMaxDelay},
+	}
+	for _, tt := range tests {
+		lo := time.Duration(float64(tt.base) * (1 - retryJitter))
+		hi := time.Duration(float64(tt.base) * (1 + retryJitter))
+		seen := make(map[time.Duration]bool)
+		for range 50 {
+			d := retryDelay(tt.failures)
+			if d < lo || d > hi {
+				t.Fatalf("retryDelay(%d) = %v, want within %v..%v", tt.failures, d, lo, hi)
+			}
+			seen[d] = true
+		}
+		if len(seen) < 2 {
+			t.Errorf("retryDelay(%d) has no jitter", tt.failures)
+		}
+	}
+}
+
+func TestRefreshFeedBacksOff(t *testing.T) {
+	openTestDB(t)
+	var fail atomic.Bool
+	fail.Store(true)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail.Load() {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`<rss version="2.0"><channel><title>Back</title><item><title>Recovered</title></item></channel></rss>`))
+	}))
+	defer server.Close()
+	url := server.URL + "/rss"
+
+	start := time.Now()
+	if err := RefreshFeed(context.Background(), url, RefreshOptions{}); err == nil {
+		t.Fatal("expected the fetch to fail")
+	}
+	state, err := feeds.GetFeedState(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.ConsecutiveFailures != 1 || state.NextRetryAt == nil || state.LastError == "" {
+		t.Fatalf("state after a failure = %+v", state)
+	}
+	if wait := state.NextRetryAt.Sub(start); wait < 3*time.Minute || wait > 7*time.Minute {
+		t.Errorf("next retry in %v, want about %v", wait, retryBaseDelay)
+	}
+
+	// Until then the feed is not fetched at all
+	fail.Store(false)
+	if err := RefreshFeed(context.Background(), url, RefreshOptions{}); !errors.Is(err, ErrFeedBackingOff) {
+		t.Fatalf("err = %v, want ErrFeedBackingOff", err)
+	}
+
+	// Once due, a successful fetch ends the failure streak
+	if err := feeds.MarkFeedFailed(url, errors.New("still down"), time.Now().Add(-time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	if err := RefreshFeed(context.Background(), url, RefreshOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if state, _ = feeds.GetFeedState(url); state.ConsecutiveFailures != 0 || state.NextRetryAt != nil {
+		t.Errorf("state after recovering = %+v", state)
+	}
+	if history, err := feeds.GetFeedErrors(url); err != nil || len(history) != 2 {
+		t.Errorf("error history = %v, %v; want both failures", history, err)
+	}
+}
@@ -0,0 +1,116 @@
+package utils
+
+import (
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/frogfromlake/Orbitalone/backend/feeds"
+	"github.com/patrickmn/go-cache"
+)
+
+// OceanIDBase is the first ocean region ID. The globe numbers its clickable ocean
+// regions from here on, above every country ID, and their feeds are stored under
+// the decimal ID in place of a country code.
+const OceanIDBase = 10000
+
+// ErrInvalidOcean is returned for ocean IDs outside the ocean ID range.
+var ErrInvalidOcean = errors.New("invalid ocean ID")
+
+// OceanChannel returns the channel code under which the feeds of an ocean region are stored.
+func OceanChannel(id string) (string, error) {
+	n, err := strconv.Atoi(strings.TrimSpace(id))
+	if err != nil || n < OceanIDBase {
+		return "", ErrInvalidOcean
+	}
+	return strconv.Itoa(n), nil
+}
+
+// keywordArticlesPerFeed bounds how many stored articles of a keyword-filtered feed are
+// searched for matches. Such feeds are usually general news feeds where only a few
+// articles match, so they are searched further back than storedArticlesPerFeed.
+const keywordArticlesPerFeed = 200
+
+// Serializes loading keyword-filtered feeds, so concurrent requests filter a feed only once
+var keywordFilterMu sync.Mutex
+
+// sourceArticles returns the ingested articles of a feed source, newest first, keeping
+// only those that match its keywords. Both unfiltered and filtered articles are read
+// through the article cache; filtered lists are kept per keyword set until the feed is
+// refreshed.
+func sourceArticles(s feeds.FeedSource) ([]NewsArticle, bool) {
+	if len(s.Keywords) == 0 {
+		return ingestedArticles(s.URL)
+	}
+
+	keywordFilterMu.Lock()
+	defer keywordFilterMu.Unlock()
+
+	key := filteredKey(s.URL)
+	signature := strings.ToLower(strings.Join(s.Keywords, "\x00"))
+	filtered := make(map[string][]NewsArticle) // keyword signature -> matching articles
+	if cached, found := feedCache.Get(key); found {
+		filtered = cached.(map[string][]NewsArticle)
+		if matching, ok := filtered[signature]; ok {
+			return matching, true
+		}
+	}
+
+	stored, err := feeds.GetArticlesByFeed(s.URL, keywordArticlesPerFeed)
+	if err != nil {
+		log.Printf("❌ Failed to load articles for %s: %v", s.URL, err)
+		return nil, false
+	}
+	if len(stored) == 0 {
+		return nil, false
+	}
+
+	matching := []NewsArticle{}
+	for _, a := range stored {
+		if article := newsArticle(a); matchesKeywords(article, s.Keywords) {
+			matching = append(matching, article)
+		}
+	}
+
+	filtered[signature] = matching
+	feedCache.Set(key, filtered, cache.NoExpiration)
+	return matching, true
+}
+
+// filteredKey is the feedCache key of a feed's keyword-filtered article lists.
+func filteredKey(feedURL string) string {
+	return feedURL + "|filtered"
+}
+
+// matchesKeywords reports whether an article's title, description or categories mention
+// any of the keywords. Keywords match case-insensitively at the start of a word, so
+// "fisher" matches "Fisheries" but "ship" does not match "leadership".
+func matchesKeywords(a NewsArticle, keywords []string) bool {
+	text := strings.ToLower(a.Title + "\n" + a.DescriptionText + "\n" + strings.Join(a.Categories, "\n"))
+	for _, k := range keywords {
+		if k = strings.ToLower(strings.TrimSpace(k)); k != "" && containsWordPrefix(text, k) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsWordPrefix reports whether prefix occurs in text at the start of a word.
+func containsWordPrefix(text, prefix string) bool {
+	for offset := 0; ; {
+		i := strings.Index(text[offset:], prefix)
+		if i < 0 {
+			return false
+		}
+		i += offset
+		before, _ := utf8.DecodeLastRuneInString(text[:i])
+		if i == 0 || !unicode.IsLetter(before) && !unicode.IsNumber(before) {
+			return true
+		}
+		offset = i + len(prefix)
+	}
+}
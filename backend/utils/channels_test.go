@@ -0,0 +1,120 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/frogfromlake/Orbitalone/backend/feeds"
+)
+
+func TestOceanChannel(t *testing.T) {
+	for id, want := range map[string]string{"10000": "10000", " 10042 ": "10042", "010001": "10001"} {
+		if got, err := OceanChannel(id); err != nil || got != want {
+			t.Errorf("OceanChannel(%q) = %q, %v; want %q", id, got, err, want)
+		}
+	}
+	for _, id := range []string{"", "9999", "276", "DE", "-10000", "1e4"} {
+		if _, err := OceanChannel(id); !errors.Is(err, ErrInvalidOcean) {
+			t.Errorf("OceanChannel(%q) accepted an invalid ID", id)
+		}
+	}
+}
+
+func TestMatchesKeywords(t *testing.T) {
+	article := NewsArticle{
+		Title:           "Fisheries ministers meet in Tromsø",
+		DescriptionText: "Quotas for the Barents Sea (cod, haddock) are on the table.",
+		Categories:      []string{"Maritime", "Politics"},
+	}
+	tests := []struct {
+		keywords []string
+		want     bool
+	}{
+		{[]string{"fisher"}, true},          // start of a word
+		{[]string{"FISHERIES"}, true},       // case-insensitive
+		{[]string{"eries"}, false},          // inside a word
+		{[]string{"tromsø"}, true},          // non-ASCII
+		{[]string{"cod"}, true},             // after punctuation
+		{[]string{"maritime"}, true},        // categories
+		{[]string{"barents sea"}, true},     // phrases
+		{[]string{"ship", "haddock"}, true}, // any keyword
+		{[]string{"ship"}, false},
+		{[]string{"  ", ""}, false},
+		{nil, false},
+	}
+	for _, tt := range tests {
+		if got := matchesKeywords(article, tt.keywords); got != tt.want {
+			t.Errorf("matchesKeywords(%q) = %v, want %v", tt.keywords, got, tt.want)
+		}
+	}
+
+	// An occurrence inside a word doesn't hide a later one at the start of a word
+	if !matchesKeywords(NewsArticle{Title: "Leadership contest in the shipping union"}, []string{"ship"}) {
+		t.Error(`"ship" did not match "shipping" after "leadership"`)
+	}
+}
+
+func TestSourceArticlesFiltersByKeywords(t *testing.T) {
+	openTestDB(t)
+	const url = "https://general.example/rss"
+	now := time.Now()
+	if err := feeds.UpsertArticles([]feeds.Article{
+		{ID: t.Name() + "-1", FeedURL: url, Title: "Container ship runs aground", PublishedAt: &now},
+		{ID: t.Name() + "-2", FeedURL: url, Title: "New party leadership elected", PublishedAt: &now},
+		{ID: t.Name() + "-3", FeedURL: url, Title: "Port of Rotterdam expands", PublishedAt: &now},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	titles := func(keywords ...string) []string {
+		articles, ok := sourceArticles(feeds.FeedSource{URL: url, Keywords: keywords})
+		if !ok {
+			t.Fatalf("no articles for %q", keywords)
+		}
+		var out []string
+		for _, a := range articles {
+			out = append(out, a.Title)
+		}
+		return out
+	}
+
+	if got := titles("ship"); len(got) != 1 || got[0] != "Container ship runs aground" {
+		t.Errorf(`"ship" kept %q`, got)
+	}
+	// Another keyword set of the same feed is filtered separately
+	if got := titles("ship", "port"); len(got) != 2 {
+		t.Errorf(`"ship", "port" kept %q`, got)
+	}
+	if got := titles(); len(got) != 3 {
+		t.Errorf("without keywords kept %q, want every article", got)
+	}
+	if got := titles("whale"); len(got) != 0 {
+		t.Errorf(`"whale" kept %q`, got)
+	}
+}
+
+func TestKeywordFilterRefreshesWithFeed(t *testing.T) {
+	openTestDB(t)
+	const url = "https://refreshing.example/rss"
+	source := feeds.FeedSource{URL: url, Keywords: []string{"ship"}}
+	now := time.Now()
+	store := func(id, title string) {
+		if err := feeds.UpsertArticles([]feeds.Article{{ID: t.Name() + id, FeedURL: url, Title: title, PublishedAt: &now}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	store("-1", "Ship sinks")
+	if articles, _ := sourceArticles(source); len(articles) != 1 {
+		t.Fatalf("%d articles before the refresh, want 1", len(articles))
+	}
+	store("-2", "Ship rescued")
+	if articles, _ := sourceArticles(source); len(articles) != 1 {
+		t.Errorf("%d articles while cached, want the cached 1", len(articles))
+	}
+	invalidateFeed(url)
+	if articles, _ := sourceArticles(source); len(articles) != 2 {
+		t.Errorf("%d articles after the refresh, want 2", len(articles))
+	}
+}
@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"time"
+
+	"github.com/frogfromlake/Orbitalone/backend/feeds"
+)
+
+// coverageWindow is the period over which recent articles are counted.
+const coverageWindow = 24 * time.Hour
+
+// CountryCoverage describes how much news is available for a country.
+type CountryCoverage struct {
+	Feeds                int        `json:"feeds"`
+	HealthyFeeds         int        `json:"healthyFeeds"`
+	Articles24h          int        `json:"articles24h"`
+	LastUpdated          *time.Time `json:"lastUpdated,omitempty"` // last successful fetch of any feed
+	TranslationAvailable bool       `json:"translationAvailable"`
+}
+
+// GetCoverage returns the news coverage of every country that has enabled feeds or a
+// translation mapping, keyed by ISO code. Countries missing from the result have neither.
+// Feeds with keywords only count the articles their keywords let through, as served.
+func GetCoverage() (map[string]CountryCoverage, error) {
+	since := time.Now().Add(-coverageWindow)
+	stats, err := feeds.ListCountryStats(since)
+	if err != nil {
+		return nil, err
+	}
+	sources, err := feeds.ListAllFeedSources()
+	if err != nil {
+		return nil, err
+	}
+
+	coverage := make(map[string]CountryCoverage, len(stats)+len(IsoToDeepLLang))
+	for code := range IsoToDeepLLang {
+		coverage[code] = CountryCoverage{TranslationAvailable: true}
+	}
+	for _, s := range stats {
+		_, translatable := IsoToDeepLLang[s.Country]
+		coverage[s.Country] = CountryCoverage{
+			Feeds:                s.Feeds,
+			HealthyFeeds:         s.HealthyFeeds,
+			Articles24h:          s.RecentArticles,
+			LastUpdated:          s.LastSuccessAt,
+			TranslationAvailable: translatable,
+		}
+	}
+
+	for _, s := range sources {
+		c, counted := coverage[s.Country]
+		if !s.Enabled || len(s.Keywords) == 0 || !counted || c.Feeds == 0 {
+			continue
+		}
+		articles, err := feeds.GetArticlesByFeedSince(s.URL, since)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range articles {
+			if matchesKeywords(newsArticle(a), s.Keywords) {
+				c.Articles24h++
+			}
+		}
+		coverage[s.Country] = c
+	}
+	return coverage, nil
+}
@@ -0,0 +1,98 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/frogfromlake/Orbitalone/backend/feeds"
+)
+
+func TestGetCoverage(t *testing.T) {
+	openTestDB(t)
+	const (
+		healthy   = "https://healthy.example/rss"
+		failing   = "https://failing.example/rss"
+		disabled  = "https://disabled.example/rss"
+		icelandic = "https://icelandic.example/rss"
+	)
+	if err := feeds.SetFeedSources("NO", []feeds.FeedSource{
+		{URL: healthy, Enabled: true},
+		{URL: failing, Enabled: true},
+		{URL: disabled, Enabled: false},
+	}, ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := feeds.SetFeedSources("IS", []feeds.FeedSource{{URL: icelandic, Enabled: true}}, ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := feeds.MarkFeedChanged(healthy, "", "", "nb"); err != nil {
+		t.Fatal(err)
+	}
+	if err := feeds.MarkFeedFailed(failing, errors.New("timeout"), time.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	old := now.Add(-2 * coverageWindow)
+	if err := feeds.UpsertArticles([]feeds.Article{
+		{ID: "1", FeedURL: healthy, Title: "Storting passes budget", PublishedAt: &now},
+		{ID: "2", FeedURL: failing, Title: "Ferry delayed by storm", PublishedAt: &now},
+		{ID: "3", FeedURL: healthy, Title: "Last week's budget talks", PublishedAt: &old},
+		{ID: "4", FeedURL: disabled, Title: "Disabled feeds are not counted", PublishedAt: &now},
+		{ID: "5", FeedURL: icelandic, Title: "Eruption ends", PublishedAt: &now},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	coverage, err := GetCoverage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	no := coverage["NO"]
+	if no.Feeds != 2 || no.HealthyFeeds != 1 || no.Articles24h != 2 || no.LastUpdated == nil || !no.TranslationAvailable {
+		t.Errorf("NO = %+v, want 2 feeds, 1 healthy, 2 recent articles and translation", no)
+	}
+	if is := coverage["IS"]; is.Feeds != 1 || is.Articles24h != 1 || is.TranslationAvailable {
+		t.Errorf("IS = %+v, want 1 feed and article without translation", is)
+	}
+	if de := coverage["DE"]; de.Feeds != 0 || !de.TranslationAvailable {
+		t.Errorf("DE = %+v, want a translatable country without feeds", de)
+	}
+}
+
+func TestGetCoverageAppliesKeywords(t *testing.T) {
+	openTestDB(t)
+	if err := feeds.SetFeedSources("NO", []feeds.FeedSource{
+		{URL: "https://news.example/rss", Enabled: true},
+		{URL: "https://general.example/rss", Enabled: true, Keywords: []string{"ship", "port"}},
+		{URL: "https://old.example/rss", Enabled: false},
+	}, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	old := now.Add(-2 * coverageWindow)
+	if err := feeds.UpsertArticles([]feeds.Article{
+		{ID: "1", FeedURL: "https://news.example/rss", Title: "Storting passes budget", PublishedAt: &now},
+		{ID: "2", FeedURL: "https://news.example/rss", Title: "Last week's budget talks", PublishedAt: &old},
+		{ID: "3", FeedURL: "https://general.example/rss", Title: "Ships stuck outside Bergen port", PublishedAt: &now},
+		{ID: "4", FeedURL: "https://general.example/rss", Title: "Leadership race heats up", PublishedAt: &now},
+		{ID: "5", FeedURL: "https://general.example/rss", Title: "Port strike ends", PublishedAt: &old},
+		{ID: "6", FeedURL: "https://old.example/rss", Title: "Disabled feeds are not counted", PublishedAt: &now},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	coverage, err := GetCoverage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	no := coverage["NO"]
+	if no.Feeds != 2 || no.Articles24h != 2 {
+		t.Errorf("NO has %d feeds and %d recent articles, want 2 and 2", no.Feeds, no.Articles24h)
+	}
+	if c := coverage["DE"]; c.Feeds != 0 || !c.TranslationAvailable {
+		t.Errorf("DE = %+v, want a translatable country without feeds", c)
+	}
+}
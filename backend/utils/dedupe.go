@@ -0,0 +1,152 @@
+package utils
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// titleSimilarityThreshold is the word-set Jaccard similarity above which two titles
+// are considered the same story.
+const titleSimilarityThreshold = 0.75
+
+// trackingParams are query parameters that never change which article a link points to.
+var trackingParams = map[string]bool{
+	"fbclid": true, "gclid": true, "dclid": true, "msclkid": true, "mc_cid": true,
+	"mc_eid": true, "ocid": true, "ref": true, "ref_src": true, "cmpid": true,
+	"at_medium": true, "at_campaign": true, "xtor": true, "icid": true,
+}
+
+// dedupeArticles removes articles that repeat an earlier one, comparing GUID, canonical
+// link and title similarity. Each group holds the articles of one feed, ordered by feed
+// priority, so the record from the higher-priority feed survives and lists the suppressed
+// ones as alternate sources.
+func dedupeArticles(groups [][]NewsArticle) [][]NewsArticle {
+	type seenArticle struct {
+		group, index int
+		words        map[string]bool
+	}
+
+	var (
+		kept   []seenArticle
+		byGUID = make(map[string]seenArticle)
+		byLink = make(map[string]seenArticle)
+		result = make([][]NewsArticle, len(groups))
+	)
+
+	for g, articles := range groups {
+		for _, a := range articles {
+			link := canonicalLink(a.Link)
+			words := titleWords(a.Title)
+
+			match, found := byGUID[guidKey(g, a.GUID)]
+			if !found && link != "" {
+				match, found = byLink[link]
+			}
+			if !found {
+				for _, k := range kept {
+					if titleSimilarity(words, k.words) >= titleSimilarityThreshold {
+						match, found = k, true
+						break
+					}
+				}
+			}
+
+			if found {
+				survivor := &result[match.group][match.index]
+				survivor.AlternateSources = append(survivor.AlternateSources, AlternateSource{
+					ID:     a.ID,
+					Source: a.Source,
+					Link:   a.Link,
+				})
+				continue
+			}
+
+			entry := seenArticle{group: g, index: len(result[g]), words: words}
+			result[g] = append(result[g], a)
+			kept = append(kept, entry)
+			if a.GUID != "" {
+				byGUID[guidKey(g, a.GUID)] = entry
+			}
+			if link != "" {
+				byLink[link] = entry
+			}
+		}
+	}
+	return result
+}
+
+// guidKey returns the key under which an article of the feed in group g is found by GUID.
+// GUIDs are only unique within their feed: "1234" in one feed says nothing about "1234" in
+// another. Absolute URLs are the exception and match across feeds.
+func guidKey(g int, guid string) string {
+	if u, err := url.Parse(guid); err == nil && u.Scheme != "" && u.Host != "" {
+		return guid
+	}
+	return strconv.Itoa(g) + "\x00" + guid
+}
+
+// canonicalLink normalizes a link for comparison: it ignores the scheme, a leading
+// "www.", trailing slashes, fragments and tracking parameters, and sorts the query.
+func canonicalLink(raw string) string {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || u.Host == "" {
+		return strings.TrimSpace(raw)
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	path := strings.TrimRight(u.EscapedPath(), "/")
+
+	query := u.Query()
+	for key := range query {
+		lower := strings.ToLower(key)
+		if strings.HasPrefix(lower, "utm_") || trackingParams[lower] {
+			query.Del(key)
+		}
+	}
+
+	canonical := host + path
+	if len(query) > 0 {
+		// Encode sorts by key
+		canonical += "?" + query.Encode()
+	}
+	return canonical
+}
+
+// titleWords splits a title into its set of lower-cased words.
+func titleWords(title string) map[string]bool {
+	words := make(map[string]bool)
+	for _, w := range strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	}) {
+		words[w] = true
+	}
+	return words
+}
+
+// titleSimilarity returns the Jaccard similarity of two word sets.
+// Very short titles never match, as they share words too easily.
+func titleSimilarity(a, b map[string]bool) float64 {
+	if len(a) < 3 || len(b) < 3 {
+		return 0
+	}
+
+	shared := 0
+	for w := range a {
+		if b[w] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+// firstNonEmpty returns the first of values that is not empty.
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,152 @@
+package utils
+
+import "testing"
+
+func TestCanonicalLink(t *testing.T) {
+	tests := []struct {
+		name, raw, want string
+	}{
+		{"scheme and www", "https://www.Example.com/news/story", "example.com/news/story"},
+		{"plain http", "http://example.com/news/story", "example.com/news/story"},
+		{"trailing slash", "https://example.com/news/story/", "example.com/news/story"},
+		{"fragment", "https://example.com/news/story#comments", "example.com/news/story"},
+		{"utm parameters", "https://example.com/story?utm_source=rss&UTM_Medium=feed", "example.com/story"},
+		{"tracking parameters", "https://example.com/story?fbclid=abc&ref=home&xtor=RSS-1", "example.com/story"},
+		{"query is sorted", "https://example.com/story?b=2&a=1", "example.com/story?a=1&b=2"},
+		{"meaningful query kept", "https://example.com/story?id=42&utm_campaign=x", "example.com/story?id=42"},
+		{"surrounding whitespace", "  https://example.com/story  ", "example.com/story"},
+		{"relative link unchanged", "/news/story", "/news/story"},
+		{"empty", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := canonicalLink(tt.raw); got != tt.want {
+				t.Errorf("canonicalLink(%q) = %q, want %q", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTitleSimilarity(t *testing.T) {
+	tests := []struct {
+		name      string
+		a, b      string
+		duplicate bool
+	}{
+		{"identical", "Storm hits the northern coast", "Storm hits the northern coast", true},
+		{"case and punctuation", "Storm hits the northern coast!", "storm hits the Northern coast", true},
+		{"one word of eight differs (7/9)", "Parliament passes new budget after long night session", "Parliament passes new budget after late night session", true},
+		{"one word of five differs (4/6)", "Storm hits the northern coast", "Storm hits the southern coast", false},
+		{"one extra word", "Parliament passes the new budget after night session", "Parliament passes new budget after night session", true},
+		{"different story", "Storm hits the northern coast", "Elections set for the autumn", false},
+		{"short titles never match", "Live updates", "Live updates", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := titleSimilarity(titleWords(tt.a), titleWords(tt.b)) >= titleSimilarityThreshold
+			if got != tt.duplicate {
+				t.Errorf("similarity of %q and %q = %.2f, duplicate = %v, want %v",
+					tt.a, tt.b, titleSimilarity(titleWords(tt.a), titleWords(tt.b)), got, tt.duplicate)
+			}
+		})
+	}
+}
+
+func TestDedupeArticles(t *testing.T) {
+	tests := []struct {
+		name     string
+		groups   [][]NewsArticle
+		wantKept []string // IDs, in order
+		wantAlts map[string][]string
+	}{
+		{
+			name: "same GUID in one feed",
+			groups: [][]NewsArticle{
+				{
+					{ID: "a", GUID: "g1", Title: "First headline of the day", Link: "https://a.example/1"},
+					{ID: "b", GUID: "g1", Title: "Something else entirely here", Link: "https://a.example/2"},
+				},
+			},
+			wantKept: []string{"a"},
+			wantAlts: map[string][]string{"a": {"b"}},
+		},
+		{
+			name: "same URL GUID",
+			groups: [][]NewsArticle{
+				{{ID: "a", GUID: "https://example.com/?p=1", Title: "First headline of the day", Link: "https://a.example/1"}},
+				{{ID: "b", GUID: "https://example.com/?p=1", Title: "Something else entirely here", Link: "https://b.example/2"}},
+			},
+			wantKept: []string{"a"},
+			wantAlts: map[string][]string{"a": {"b"}},
+		},
+		{
+			name: "same non-URL GUID in two feeds",
+			groups: [][]NewsArticle{
+				{{ID: "a", GUID: "1234", Title: "First headline of the day", Link: "https://a.example/1"}},
+				{{ID: "b", GUID: "1234", Title: "Something else entirely here", Link: "https://b.example/2"}},
+			},
+			wantKept: []string{"a", "b"},
+		},
+		{
+			name: "same canonical link",
+			groups: [][]NewsArticle{
+				{{ID: "a", Title: "First headline of the day", Link: "https://www.example.com/story/"}},
+				{{ID: "b", Title: "Different words in this title", Link: "http://example.com/story?utm_source=rss"}},
+			},
+			wantKept: []string{"a"},
+			wantAlts: map[string][]string{"a": {"b"}},
+		},
+		{
+			name: "near-duplicate title",
+			groups: [][]NewsArticle{
+				{{ID: "a", Title: "Storm hits the northern coast", Link: "https://a.example/1"}},
+				{{ID: "b", Title: "Storm hits the northern coast:", Link: "https://b.example/2"}},
+			},
+			wantKept: []string{"a"},
+			wantAlts: map[string][]string{"a": {"b"}},
+		},
+		{
+			name: "distinct articles",
+			groups: [][]NewsArticle{
+				{{ID: "a", Title: "Storm hits the northern coast", Link: "https://a.example/1"}},
+				{{ID: "b", Title: "Elections set for the autumn", Link: "https://b.example/2"}},
+			},
+			wantKept: []string{"a", "b"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var kept []string
+			alts := make(map[string][]string)
+			for _, group := range dedupeArticles(tt.groups) {
+				for _, a := range group {
+					kept = append(kept, a.ID)
+					for _, alt := range a.AlternateSources {
+						alts[a.ID] = append(alts[a.ID], alt.ID)
+					}
+				}
+			}
+			if !equalStrings(kept, tt.wantKept) {
+				t.Errorf("kept %v, want %v", kept, tt.wantKept)
+			}
+			for id, want := range tt.wantAlts {
+				if !equalStrings(alts[id], want) {
+					t.Errorf("alternates of %s = %v, want %v", id, alts[id], want)
+				}
+			}
+		})
+	}
+}
+
+// equalStrings reports whether two string slices have the same elements in the same order.
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
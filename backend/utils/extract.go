@@ -0,0 +1,232 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/frogfromlake/Orbitalone/backend/feeds"
+	"golang.org/x/net/html"
+)
+
+const (
+	maxExtractionsPerRefresh = 10               // pages fetched per feed refresh at most
+	extractionPageTimeout    = 10 * time.Second // per article page, connecting to reading the body
+	maxArticlePageBytes      = 2 << 20          // ignore anything past 2 MiB of HTML
+	leadMaxRunes             = 400              // cut longer lead paragraphs at a word boundary
+	wordsPerMinute           = 200              // average adult reading speed
+	minParagraphRunes        = 40               // shorter paragraphs are usually captions or bylines
+	stubDescriptionRunes     = 40               // descriptions shorter than this carry no real summary
+	articleContentSelector   = "p, pre, blockquote"
+)
+
+var (
+	// Containers whose class or id suggests boilerplate rather than article text
+	negativeHint = regexp.MustCompile(`(?i)comment|footer|sidebar|nav|menu|share|social|promo|related|teaser|banner|cookie|newsletter|subscribe|advert|\bad-`)
+	// Containers whose class or id suggests the article body
+	positiveHint = regexp.MustCompile(`(?i)article|content|story|body|main|entry|post|text`)
+)
+
+// stubMarkers end descriptions that were cut off by the feed.
+var stubMarkers = []string{
+	"…", "...", "[…]", "[...]",
+	"read more", "continue reading", "mehr lesen", "weiterlesen", "lire la suite", "leer más", "leggi tutto",
+}
+
+// needsExtraction reports whether a description is empty, too short to summarize the
+// article, or cut off with an ellipsis or "read more" link. Complete one-sentence
+// summaries are kept.
+func needsExtraction(descriptionText string) bool {
+	text := strings.ToLower(strings.TrimSpace(descriptionText))
+	if utf8.RuneCountInString(text) < stubDescriptionRunes {
+		return true
+	}
+	// Links often end in an arrow or a colon: "Read more »"
+	text = strings.TrimRight(text, " :»›>→")
+	for _, marker := range stubMarkers {
+		if strings.HasSuffix(text, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// extractMissingContent fetches the pages of articles with stub descriptions that have
+// not been extracted before and stores their lead paragraph and reading time.
+// Pages are fetched one after the other, so a site gets at most one request at a time
+// from a feed. It returns the number of pages fetched.
+func extractMissingContent(ctx context.Context, feedURL string, articles []feeds.Article) int {
+	done, err := feeds.ExtractedArticleIDs(feedURL)
+	if err != nil {
+		log.Printf("⚠️ %v", err)
+		return 0
+	}
+
+	attempts := 0
+	for _, a := range articles {
+		if attempts >= maxExtractionsPerRefresh || ctx.Err() != nil {
+			break
+		}
+		if done[a.ID] || a.Link == "" || !needsExtraction(htmlToText(a.Description)) {
+			continue
+		}
+		attempts++
+
+		lead, minutes, err := extractArticle(ctx, a.Link)
+		if err != nil {
+			log.Printf("⚠️ Failed to extract %s: %v", a.Link, err)
+			if ctx.Err() != nil {
+				break
+			}
+		}
+		// Record failures too, so broken pages are not fetched on every poll
+		if err := feeds.SetArticleExtraction(a.ID, lead, minutes); err != nil {
+			log.Printf("⚠️ %v", err)
+		}
+	}
+	return attempts
+}
+
+// extractArticle downloads an article page and returns its lead paragraph and the
+// estimated reading time in minutes of its main content.
+func extractArticle(ctx context.Context, pageURL string) (string, int, error) {
+	ctx, cancel := context.WithTimeout(ctx, extractionPageTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL, nil)
+	if err != nil {
+		return "", 0, err
+	}
+	req.Header.Set("Accept", "text/html")
+
+	resp, err := parser.Client.Do(req)
+	if err != nil {
+		return "", 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", 0, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "" && !strings.Contains(ct, "html") {
+		return "", 0, fmt.Errorf("unexpected content type %q", ct)
+	}
+
+	doc, err := goquery.NewDocumentFromReader(io.LimitReader(resp.Body, maxArticlePageBytes))
+	if err != nil {
+		return "", 0, err
+	}
+
+	paragraphs := mainContent(doc)
+	if len(paragraphs) == 0 {
+		return "", 0, nil
+	}
+	return leadParagraph(paragraphs), readingMinutes(paragraphs), nil
+}
+
+// mainContent returns the paragraphs of the element most likely to hold the article
+// body. Like readability, it scores each paragraph's parent (and, half as much, its
+// grandparent) by the amount of text it holds, adjusted by class and id hints.
+func mainContent(doc *goquery.Document) []string {
+	doc.Find("script, style, noscript, nav, header, footer, aside, form, iframe, figure, button").Remove()
+
+	scores := make(map[*html.Node]float64)
+	var order []*html.Node
+	addScore := func(n *html.Node, score float64) {
+		if n == nil || n.Type != html.ElementNode {
+			return
+		}
+		if _, seen := scores[n]; !seen {
+			order = append(order, n)
+			scores[n] = hintScore(n)
+		}
+		scores[n] += score
+	}
+
+	doc.Find(articleContentSelector).Each(func(_ int, p *goquery.Selection) {
+		text := strings.TrimSpace(p.Text())
+		length := utf8.RuneCountInString(text)
+		if length < minParagraphRunes {
+			return
+		}
+		score := 1 + float64(strings.Count(text, ",")) + math.Min(float64(length)/100, 3)
+
+		node := p.Get(0)
+		addScore(node.Parent, score)
+		if node.Parent != nil {
+			addScore(node.Parent.Parent, score/2)
+		}
+	})
+
+	var best *html.Node
+	for _, n := range order {
+		if best == nil || scores[n] > scores[best] {
+			best = n
+		}
+	}
+	if best == nil {
+		return nil
+	}
+
+	var paragraphs []string
+	goquery.NewDocumentFromNode(best).Find(articleContentSelector).Each(func(_ int, p *goquery.Selection) {
+		if text := strings.Join(strings.Fields(p.Text()), " "); text != "" {
+			paragraphs = append(paragraphs, text)
+		}
+	})
+	return paragraphs
+}
+
+// hintScore returns the starting score of a container from its class and id names.
+func hintScore(n *html.Node) float64 {
+	hints := attr(n, "class") + " " + attr(n, "id")
+	score := 0.0
+	if negativeHint.MatchString(hints) {
+		score -= 25
+	}
+	if positiveHint.MatchString(hints) {
+		score += 25
+	}
+	if n.Data == "article" || n.Data == "main" {
+		score += 10
+	}
+	return score
+}
+
+// leadParagraph returns the first substantial paragraph, shortened at a word boundary.
+func leadParagraph(paragraphs []string) string {
+	lead := paragraphs[0]
+	for _, p := range paragraphs {
+		if utf8.RuneCountInString(p) >= minParagraphRunes {
+			lead = p
+			break
+		}
+	}
+
+	runes := []rune(lead)
+	if len(runes) <= leadMaxRunes {
+		return lead
+	}
+	cut := string(runes[:leadMaxRunes])
+	if i := strings.LastIndex(cut, " "); i > 0 {
+		cut = cut[:i]
+	}
+	return strings.TrimRight(cut, " ,;:") + "…"
+}
+
+// readingMinutes estimates reading time from the word count, rounding up to at least one minute.
+func readingMinutes(paragraphs []string) int {
+	words := 0
+	for _, p := range paragraphs {
+		words += len(strings.Fields(p))
+	}
+	return max(1, (words+wordsPerMinute-1)/wordsPerMinute)
+}
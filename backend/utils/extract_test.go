@@ -0,0 +1,88 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/frogfromlake/Orbitalone/backend/feeds"
+)
+
+func TestNeedsExtraction(t *testing.T) {
+	complete := "The council approved the new harbour budget after a long debate on Tuesday."
+	for text, want := range map[string]bool{
+		"":                         true,
+		"Short teaser":             true,
+		complete:                   false,
+		complete[:60] + "…":        true,
+		complete[:60] + " [...]":   true,
+		complete + " Read more »":  true,
+		complete + " Weiterlesen:": true,
+		"Ellipses inside… are not the end of it, the summary goes on.": false,
+	} {
+		if got := needsExtraction(text); got != want {
+			t.Errorf("needsExtraction(%q) = %v, want %v", text, got, want)
+		}
+	}
+}
+
+// articlePage is an article surrounded by the boilerplate of a news site.
+var articlePage = `<html><body>
+<nav class="menu"><p>Home · World · Business · Sport · Culture · Opinion · Weather</p></nav>
+<div class="article-body">
+	<p class="byline">By A. Writer</p>
+	<p>The harbour authority confirmed on Monday that the new container terminal will open next spring.</p>
+	` + strings.Repeat(`<p>Officials expect the terminal to double the port's capacity and to create hundreds of jobs in the region.</p>`, 30) + `
+</div>
+<div id="comments"><p>Great news for the town, finally something is happening down at the port!</p></div>
+<footer><p>© News Site. All rights reserved. Contact us for licensing and syndication.</p></footer>
+</body></html>`
+
+func TestExtractMissingContent(t *testing.T) {
+	openTestDB(t)
+	var fetches atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		if r.URL.Path == "/broken" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, articlePage)
+	}))
+	defer server.Close()
+
+	const feed = "https://port.example/rss"
+	articles := []feeds.Article{
+		{ID: "stub", FeedURL: feed, Link: server.URL + "/stub", Title: "Terminal to open", Description: "Terminal news…"},
+		{ID: "broken", FeedURL: feed, Link: server.URL + "/broken", Title: "Gone", Description: ""},
+		{ID: "complete", FeedURL: feed, Link: server.URL + "/complete", Title: "Complete",
+			Description: "The harbour authority confirmed that the new terminal opens next spring."},
+	}
+	if err := feeds.UpsertArticles(articles); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := extractMissingContent(context.Background(), feed, articles); n != 2 {
+		t.Errorf("fetched %d pages, want the 2 stubs", n)
+	}
+	// Pages are only ever tried once, whether extraction worked or not
+	if n := extractMissingContent(context.Background(), feed, articles); n != 0 || fetches.Load() != 2 {
+		t.Errorf("second pass fetched %d pages (%d requests in total)", n, fetches.Load())
+	}
+
+	stub, err := feeds.GetArticle("stub")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(stub.Lead, "The harbour authority confirmed on Monday") {
+		t.Errorf("lead = %q", stub.Lead)
+	}
+	if stub.ReadingMins != 3 { // 15 + 30×18 words at 200 words a minute
+		t.Errorf("reading time %d minutes, want 3", stub.ReadingMins)
+	}
+}
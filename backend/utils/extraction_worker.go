@@ -0,0 +1,118 @@
+package utils
+
+import (
+	"context"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+
+	"github.com/frogfromlake/Orbitalone/backend/feeds"
+)
+
+// extractionQueueSize bounds how many feeds may wait for content extraction.
+// Feeds refreshed while the queue is full are extracted after a later refresh.
+const extractionQueueSize = 64
+
+// extractionJob holds the freshly ingested articles of a feed whose pages should be extracted.
+type extractionJob struct {
+	feedURL  string
+	articles []feeds.Article
+}
+
+// ExtractionWorker fetches article pages for stub descriptions in the background, so
+// slow article sites never hold up the feed poller.
+type ExtractionWorker struct {
+	workers int
+	jobs    chan extractionJob
+	queued  sync.Map // feed URL -> struct{}, feeds waiting for or in extraction
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+// NewExtractionWorker creates a worker pool with the given number of goroutines.
+// Call Start to run it.
+func NewExtractionWorker(workers int) *ExtractionWorker {
+	if workers < 1 {
+		workers = 1
+	}
+	return &ExtractionWorker{
+		workers: workers,
+		jobs:    make(chan extractionJob, extractionQueueSize),
+	}
+}
+
+// ExtractionWorkersFromEnv reads the number of extraction workers from
+// EXTRACTION_WORKERS, defaulting to 2.
+func ExtractionWorkersFromEnv() int {
+	if raw := os.Getenv("EXTRACTION_WORKERS"); raw != "" {
+		if n, err := strconv.Atoi(raw); err == nil && n > 0 {
+			return n
+		}
+		log.Printf("⚠️  Invalid EXTRACTION_WORKERS %q, using 2", raw)
+	}
+	return 2
+}
+
+// Start launches the workers in the background.
+func (w *ExtractionWorker) Start(ctx context.Context) {
+	ctx, w.cancel = context.WithCancel(ctx)
+	w.done = make(chan struct{})
+
+	var wg sync.WaitGroup
+	for i := 0; i < w.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case job := <-w.jobs:
+					w.run(ctx, job)
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(w.done)
+	}()
+	log.Printf("📰 Extraction worker started (%d workers)", w.workers)
+}
+
+// Stop cancels in-flight extractions and blocks until all workers have exited.
+func (w *ExtractionWorker) Stop() {
+	if w.cancel == nil {
+		return
+	}
+	w.cancel()
+	<-w.done
+	log.Println("🛑 Extraction worker stopped")
+}
+
+// Enqueue queues the articles of a feed for extraction unless the feed is already queued.
+// It never blocks; when the queue is full the feed is dropped and queued again by its
+// next refresh.
+func (w *ExtractionWorker) Enqueue(feedURL string, articles []feeds.Article) {
+	if _, loaded := w.queued.LoadOrStore(feedURL, struct{}{}); loaded {
+		return
+	}
+
+	select {
+	case w.jobs <- extractionJob{feedURL: feedURL, articles: articles}:
+	default:
+		log.Printf("⚠️  Extraction queue full – skipping %s until its next refresh", feedURL)
+		w.queued.Delete(feedURL)
+	}
+}
+
+// run extracts the pages of a queued feed and releases it.
+func (w *ExtractionWorker) run(ctx context.Context, job extractionJob) {
+	defer w.queued.Delete(job.feedURL)
+
+	if extractMissingContent(ctx, job.feedURL, job.articles) > 0 {
+		// Reload with the extracted leads on next read
+		invalidateFeed(job.feedURL)
+	}
+}
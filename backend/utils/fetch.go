@@ -0,0 +1,62 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/frogfromlake/Orbitalone/backend/feeds"
+	"github.com/mmcdole/gofeed"
+)
+
+// errNotModified is returned by fetchFeed when the publisher answered 304 Not Modified.
+var errNotModified = errors.New("feed not modified")
+
+// fetchResult is a freshly downloaded feed together with its new HTTP validators.
+type fetchResult struct {
+	Feed         *gofeed.Feed
+	ETag         string
+	LastModified string
+}
+
+// fetchFeed downloads a feed with the shared parser's client, sending If-None-Match and
+// If-Modified-Since from the stored state so unchanged feeds are not re-downloaded.
+func fetchFeed(ctx context.Context, url string, state feeds.FeedState) (*fetchResult, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if state.ETag != "" {
+		req.Header.Set("If-None-Match", state.ETag)
+	}
+	if state.LastModified != "" {
+		req.Header.Set("If-Modified-Since", state.LastModified)
+	}
+
+	resp, err := parser.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, errNotModified
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, gofeed.HTTPError{
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+		}
+	}
+
+	feed, err := parser.Parse(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return &fetchResult{
+		Feed:         feed,
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	}, nil
+}
@@ -0,0 +1,101 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/frogfromlake/Orbitalone/backend/feeds"
+)
+
+// openTestDB points the feeds package at a private in-memory database.
+func openTestDB(t *testing.T) {
+	t.Helper()
+	if err := feeds.Open("file:" + t.Name() + "?mode=memory&cache=shared&_pragma=busy_timeout(5000)"); err != nil {
+		t.Fatalf("opening test database: %v", err)
+	}
+}
+
+// versionedFeed serves an RSS feed with one item per version, answering conditional
+// requests for the current version with 304 Not Modified.
+type versionedFeed struct {
+	mu      sync.Mutex
+	version int
+	headers []http.Header // request headers, in order
+}
+
+func (f *versionedFeed) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.headers = append(f.headers, r.Header.Clone())
+
+	etag := fmt.Sprintf(`"v%d"`, f.version)
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Last-Modified", fmt.Sprintf("Mon, 0%d Jun 2025 10:00:00 GMT", f.version+1))
+	fmt.Fprint(w, `<?xml version="1.0"?><rss version="2.0"><channel><title>Versioned</title>`)
+	for v := 0; v <= f.version; v++ {
+		fmt.Fprintf(w, `<item><guid>item-%d</guid><title>Story number %d</title></item>`, v, v)
+	}
+	fmt.Fprint(w, `</channel></rss>`)
+}
+
+func TestRefreshFeedConditionalRequests(t *testing.T) {
+	openTestDB(t)
+	feed := &versionedFeed{}
+	server := httptest.NewServer(feed)
+	defer server.Close()
+	url := server.URL + "/rss"
+
+	refresh := func() {
+		t.Helper()
+		if err := RefreshFeed(context.Background(), url, RefreshOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	stored := func() int {
+		t.Helper()
+		articles, err := feeds.GetArticlesByFeed(url, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return len(articles)
+	}
+
+	refresh()
+	refresh() // unchanged: answered with 304
+	feed.mu.Lock()
+	feed.version = 1
+	feed.mu.Unlock()
+	refresh()
+
+	want := []struct{ ifNoneMatch, ifModifiedSince string }{
+		{"", ""},
+		{`"v0"`, "Mon, 01 Jun 2025 10:00:00 GMT"},
+		{`"v0"`, "Mon, 01 Jun 2025 10:00:00 GMT"},
+	}
+	for i, w := range want {
+		h := feed.headers[i]
+		if h.Get("If-None-Match") != w.ifNoneMatch || h.Get("If-Modified-Since") != w.ifModifiedSince {
+			t.Errorf("request %d sent If-None-Match %q and If-Modified-Since %q, want %q and %q",
+				i, h.Get("If-None-Match"), h.Get("If-Modified-Since"), w.ifNoneMatch, w.ifModifiedSince)
+		}
+	}
+	if n := stored(); n != 2 {
+		t.Errorf("stored %d articles after the feed changed, want 2", n)
+	}
+
+	state, err := feeds.GetFeedState(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.ETag != `"v1"` || state.CheckedAt == nil || state.ConsecutiveFailures != 0 {
+		t.Errorf("state = %+v, want the new validators of a healthy feed", state)
+	}
+}
@@ -0,0 +1,64 @@
+package utils
+
+import (
+	"github.com/frogfromlake/Orbitalone/backend/feeds"
+	"github.com/frogfromlake/Orbitalone/backend/localization"
+	"github.com/mmcdole/gofeed"
+)
+
+// resolveLanguages sets the language of each article and returns the feed's language,
+// the one most of its items are in.
+// items and articles are parallel slices. The first of these wins:
+//   - the language configured for the feed source by an admin
+//   - the item's dc:language
+//   - the n-gram classifier on the item's title and description, which keeps the feed's
+//     <language> or xml:lang declaration unless the text is clearly in another language
+//
+// Feeds often declare a default language for all their items, so the declaration is only
+// a prior. Items the classifier cannot judge fall back to it, or without one to the
+// majority language of the feed.
+func resolveLanguages(feed *gofeed.Feed, items []*gofeed.Item, articles []feeds.Article, configured string) string {
+	if lang := localization.NormalizeLanguage(configured); lang != "" {
+		for i := range articles {
+			articles[i].Language = lang
+		}
+		return lang
+	}
+	declared := localization.NormalizeLanguage(feed.Language)
+
+	votes := make(map[string]int)
+	var undecided []int
+	for i, item := range items {
+		if lang := itemLanguage(item); lang != "" {
+			articles[i].Language = lang
+			continue
+		}
+		if lang, ok := localization.DetectLanguageWithPrior(htmlToText(item.Title+". "+item.Description), declared); ok {
+			articles[i].Language = lang
+			votes[lang]++
+			continue
+		}
+		undecided = append(undecided, i)
+	}
+
+	// The declared language only decides ties, so a feed declaring the wrong one is
+	// recorded with the language its items are actually in
+	feedLang := declared
+	for lang, n := range votes {
+		if n > votes[feedLang] || (n == votes[feedLang] && feedLang != declared && lang < feedLang) {
+			feedLang = lang
+		}
+	}
+	for _, i := range undecided {
+		articles[i].Language = feedLang
+	}
+	return feedLang
+}
+
+// itemLanguage returns the item's own dc:language declaration, if any.
+func itemLanguage(item *gofeed.Item) string {
+	if item.DublinCoreExt == nil || len(item.DublinCoreExt.Language) == 0 {
+		return ""
+	}
+	return localization.NormalizeLanguage(item.DublinCoreExt.Language[0])
+}
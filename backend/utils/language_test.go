@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/frogfromlake/Orbitalone/backend/feeds"
+	"github.com/mmcdole/gofeed"
+	ext "github.com/mmcdole/gofeed/extensions"
+)
+
+func TestResolveLanguages(t *testing.T) {
+	german := &gofeed.Item{Title: "Bundesregierung beschließt neues Klimapaket für die Industrie"}
+	french := &gofeed.Item{Title: "Le gouvernement annonce une nouvelle réforme des retraites"}
+	short := &gofeed.Item{Title: "Live"}
+	tagged := &gofeed.Item{Title: "Live", DublinCoreExt: &ext.DublinCoreExtension{Language: []string{"it-IT"}}}
+
+	tests := []struct {
+		name       string
+		declared   string
+		configured string
+		items      []*gofeed.Item
+		want       []string
+		wantFeed   string
+	}{
+		{"classified per item", "", "", []*gofeed.Item{german, french, german}, []string{"de", "fr", "de"}, "de"},
+		{"undecided items take the majority", "", "", []*gofeed.Item{short, french}, []string{"fr", "fr"}, "fr"},
+		{"declaration fills in the undecided", "de", "", []*gofeed.Item{short, german}, []string{"de", "de"}, "de"},
+		{"wrong declaration is overruled", "en", "", []*gofeed.Item{german, german, short}, []string{"de", "de", "en"}, "de"},
+		{"item declaration wins", "de", "", []*gofeed.Item{tagged, german}, []string{"it", "de"}, "de"},
+		{"configured language wins", "en", "fr", []*gofeed.Item{german, tagged}, []string{"fr", "fr"}, "fr"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			articles := make([]feeds.Article, len(tt.items))
+			feedLang := resolveLanguages(&gofeed.Feed{Language: tt.declared}, tt.items, articles, tt.configured)
+
+			for i, a := range articles {
+				if a.Language != tt.want[i] {
+					t.Errorf("item %d: language %q, want %q", i, a.Language, tt.want[i])
+				}
+			}
+			if feedLang != tt.wantFeed {
+				t.Errorf("feed language %q, want %q", feedLang, tt.wantFeed)
+			}
+		})
+	}
+}
+
+func TestSourceLanguage(t *testing.T) {
+	tests := []struct {
+		country, detected string
+		want              string
+		ok                bool
+	}{
+		{"BE", "fr", "FR", true}, // the article's own language
+		{"BE", "", "NL", true},   // not detected: the country's main language
+		{"CH", "rm", "DE", true}, // unsupported by the provider: the country's language
+		{"IS", "is", "", false},  // neither is supported
+		{"UA", "uk", "UK", true},
+	}
+	for _, tt := range tests {
+		got, ok := sourceLanguage(tt.country, &NewsArticle{ID: "a", Language: tt.detected})
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("sourceLanguage(%s, %q) = %q, %v; want %q, %v", tt.country, tt.detected, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package utils
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/frogfromlake/Orbitalone/backend/feeds"
+	"github.com/mmcdole/gofeed"
+	ext "github.com/mmcdole/gofeed/extensions"
+	"golang.org/x/net/html"
+)
+
+// resolveImage picks the best image for a feed item. It prefers the widest image in
+// media:content, then media:thumbnail, then the parser's own pick, image enclosures,
+// and finally the first <img> found in the item's content or description.
+func resolveImage(item *gofeed.Item) string {
+	media := item.Extensions["media"]
+
+	var contents, thumbnails []ext.Extension
+	collect := func(group map[string][]ext.Extension) {
+		contents = append(contents, group["content"]...)
+		thumbnails = append(thumbnails, group["thumbnail"]...)
+	}
+	collect(media)
+	for _, group := range media["group"] {
+		collect(group.Children)
+	}
+
+	best, bestWidth := "", -1
+	for _, c := range contents {
+		url := c.Attrs["url"]
+		if url == "" || !isImageMedia(c.Attrs["medium"], c.Attrs["type"], url) {
+			continue
+		}
+		if width, _ := strconv.Atoi(c.Attrs["width"]); width > bestWidth {
+			best, bestWidth = url, width
+		}
+	}
+	if best != "" {
+		return best
+	}
+
+	for _, t := range thumbnails {
+		if url := t.Attrs["url"]; url != "" {
+			return url
+		}
+	}
+
+	if item.Image != nil && item.Image.URL != "" {
+		return item.Image.URL
+	}
+
+	for _, enc := range item.Enclosures {
+		if enc.URL != "" && strings.HasPrefix(enc.Type, "image/") {
+			return enc.URL
+		}
+	}
+
+	return firstNonEmpty(firstImageSrc(item.Content), firstImageSrc(item.Description))
+}
+
+// isImageMedia reports whether a media:content entry describes an image.
+// Entries without medium or type are judged by their file extension.
+func isImageMedia(medium, mimeType, url string) bool {
+	if medium != "" {
+		return medium == "image"
+	}
+	if mimeType != "" {
+		return strings.HasPrefix(mimeType, "image/")
+	}
+	path := strings.ToLower(strings.SplitN(url, "?", 2)[0])
+	for _, suffix := range []string{".jpg", ".jpeg", ".png", ".webp", ".gif", ".avif"} {
+		if strings.HasSuffix(path, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// firstImageSrc returns the src of the first <img> in an HTML fragment.
+func firstImageSrc(fragment string) string {
+	if !strings.Contains(fragment, "<img") {
+		return ""
+	}
+
+	tokenizer := html.NewTokenizer(strings.NewReader(fragment))
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			return ""
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := tokenizer.Token()
+			if token.Data != "img" {
+				continue
+			}
+			for _, attr := range token.Attr {
+				if attr.Key == "src" && attr.Val != "" {
+					return attr.Val
+				}
+			}
+		}
+	}
+}
+
+// authorNames returns the display names of an item's authors, using the e-mail
+// address for authors without a name.
+func authorNames(item *gofeed.Item) []string {
+	var names []string
+	for _, p := range item.Authors {
+		if p == nil {
+			continue
+		}
+		if name := firstNonEmpty(strings.TrimSpace(p.Name), strings.TrimSpace(p.Email)); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// itemEnclosures converts the parser's enclosures into the stored representation.
+func itemEnclosures(item *gofeed.Item) []feeds.Enclosure {
+	var result []feeds.Enclosure
+	for _, enc := range item.Enclosures {
+		if enc == nil || enc.URL == "" {
+			continue
+		}
+		result = append(result, feeds.Enclosure{URL: enc.URL, Type: enc.Type, Length: enc.Length})
+	}
+	return result
+}
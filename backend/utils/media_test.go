@@ -0,0 +1,80 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// mediaFeed has one item per way a feed can attach an image, identified by its GUID.
+const mediaFeed = `<?xml version="1.0"?>
+<rss version="2.0" xmlns:media="http://search.yahoo.com/mrss/">
+<channel><title>Media</title>
+<item><guid>widest-content</guid><title>a</title>
+	<media:content url="https://img.example/small.jpg" medium="image" width="320"/>
+	<media:content url="https://img.example/video.mp4" medium="video" width="1920"/>
+	<media:content url="https://img.example/large.jpg" medium="image" width="1280"/>
+	<media:thumbnail url="https://img.example/thumb.jpg"/>
+</item>
+<item><guid>grouped-content</guid><title>b</title>
+	<media:group><media:content url="https://img.example/grouped.webp?w=800"/></media:group>
+</item>
+<item><guid>thumbnail</guid><title>c</title>
+	<media:content url="https://img.example/clip.mp4"/>
+	<media:thumbnail url="https://img.example/thumb.jpg"/>
+</item>
+<item><guid>enclosure</guid><title>d</title><author>tips@example.com</author>
+	<enclosure url="https://img.example/podcast.mp3" type="audio/mpeg" length="123"/>
+	<enclosure url="https://img.example/cover.png" type="image/png" length="45"/>
+</item>
+<item><guid>description</guid><title>e</title>
+	<description><![CDATA[<p>Text</p><img alt="" src="https://img.example/inline.gif"><img src="https://img.example/second.gif">]]></description>
+</item>
+<item><guid>none</guid><title>f</title><description>No pictures here</description>
+	<author>desk@example.com (News Desk)</author>
+	<category>Politics</category><category>Europe</category>
+</item>
+</channel></rss>`
+
+func TestRichItemFields(t *testing.T) {
+	feed, err := gofeed.NewParser().ParseString(mediaFeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	items := make(map[string]*gofeed.Item)
+	for _, item := range feed.Items {
+		items[item.GUID] = item
+	}
+
+	images := map[string]string{
+		"widest-content":  "https://img.example/large.jpg",
+		"grouped-content": "https://img.example/grouped.webp?w=800",
+		"thumbnail":       "https://img.example/thumb.jpg",
+		"enclosure":       "https://img.example/cover.png",
+		"description":     "https://img.example/inline.gif",
+		"none":            "",
+	}
+	for guid, want := range images {
+		if got := resolveImage(items[guid]); got != want {
+			t.Errorf("%s: image %q, want %q", guid, got, want)
+		}
+	}
+
+	enclosures := itemEnclosures(items["enclosure"])
+	if len(enclosures) != 2 || enclosures[0].Type != "audio/mpeg" || enclosures[0].Length != "123" {
+		t.Errorf("enclosures = %+v", enclosures)
+	}
+
+	// Authors are named by their name, or their address when they have none
+	none := items["none"]
+	if got := strings.Join(authorNames(none), ", "); got != "News Desk" {
+		t.Errorf("authors = %q, want News Desk", got)
+	}
+	if got := strings.Join(authorNames(items["enclosure"]), ", "); got != "tips@example.com" {
+		t.Errorf("authors = %q, want tips@example.com", got)
+	}
+	if got := strings.Join(none.Categories, ", "); got != "Politics, Europe" {
+		t.Errorf("categories = %q", got)
+	}
+}
@@ -0,0 +1,102 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sort"
+
+	"github.com/frogfromlake/Orbitalone/backend/feeds"
+	"golang.org/x/sync/errgroup"
+)
+
+// Per-country states of a multi-country query.
+const (
+	CountryOK      = "ok"
+	CountryEmpty   = "empty"    // feeds are configured but nothing is ingested yet
+	CountryNoFeeds = "no_feeds" // no feeds are configured for the country
+	CountryError   = "error"
+)
+
+// CountryNews is the outcome of one country of a multi-country query.
+type CountryNews struct {
+	Country  string        `json:"country"`
+	Status   string        `json:"status"`
+	Error    string        `json:"error,omitempty"`
+	Articles []NewsArticle `json:"articles,omitempty"`
+}
+
+// multiCountryConcurrency bounds how many countries of one query are loaded at once.
+const multiCountryConcurrency = 8
+
+// GetNewsByCountries runs GetNewsByCountry for every code, several at a time, and returns
+// the results in the order of codes. A failing country is reported in its entry and does
+// not affect the others. Countries not started before ctx is cancelled are reported as
+// failed. Articles are tagged with their country, so they can be told apart once merged.
+func GetNewsByCountries(ctx context.Context, codes []string, opts NewsOptions) []CountryNews {
+	results := make([]CountryNews, len(codes))
+
+	var g errgroup.Group
+	g.SetLimit(multiCountryConcurrency)
+	for i, code := range codes {
+		g.Go(func() error {
+			results[i] = countryNews(ctx, code, opts)
+			return nil
+		})
+	}
+	g.Wait()
+	return results
+}
+
+// countryNews loads the news of one country of a multi-country query.
+func countryNews(ctx context.Context, code string, opts NewsOptions) CountryNews {
+	result := CountryNews{Country: code, Status: CountryOK}
+	if ctx.Err() != nil {
+		result.Status, result.Error = CountryError, "Request cancelled"
+		return result
+	}
+
+	articles, err := GetNewsByCountry(code, opts)
+	switch {
+	case errors.Is(err, feeds.ErrNoFeeds):
+		result.Status = CountryNoFeeds
+	case err != nil:
+		log.Printf("❌ Failed to fetch news for %s: %v", code, err)
+		result.Status, result.Error = CountryError, "Failed to fetch news"
+	case len(articles) == 0:
+		result.Status = CountryEmpty
+	default:
+		for i := range articles {
+			articles[i].Country = code
+		}
+		result.Articles = articles
+	}
+	return result
+}
+
+// MergeCountryNews moves the articles of all countries into a single list, newest first,
+// dropping articles that appear under several countries. The per-country entries keep
+// their states but lose their articles.
+func MergeCountryNews(results []CountryNews) []NewsArticle {
+	merged := []NewsArticle{}
+	seen := make(map[string]bool)
+	for i := range results {
+		for _, a := range results[i].Articles {
+			if !seen[a.ID] {
+				seen[a.ID] = true
+				merged = append(merged, a)
+			}
+		}
+		results[i].Articles = nil
+	}
+
+	// Undated articles go last
+	sort.SliceStable(merged, func(i, j int) bool {
+		a, b := merged[i].PublishedAt, merged[j].PublishedAt
+		if a == nil || b == nil {
+			return a != nil && b == nil
+		}
+		return a.After(*b)
+	})
+	return merged
+}
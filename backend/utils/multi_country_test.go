@@ -0,0 +1,89 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/frogfromlake/Orbitalone/backend/feeds"
+)
+
+func TestGetNewsByCountriesMergeOrder(t *testing.T) {
+	openTestDB(t)
+	base := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	shared := "https://wire.merge-order.example/rss"
+
+	// More countries than are loaded at once, each with a feed whose articles interleave in
+	// time with the other countries'; DE and FR also share a wire feed
+	var codes []string
+	for i, code := range []string{"PT", "ES", "FR", "BE", "NL", "DE", "DK", "SE", "NO", "FI", "EE", "LV"} {
+		codes = append(codes, code)
+		url := fmt.Sprintf("https://%s.merge-order.example/rss", code)
+		sources := []feeds.FeedSource{{URL: url, Enabled: true}}
+		if code == "DE" || code == "FR" {
+			sources = append(sources, feeds.FeedSource{URL: shared, Enabled: true})
+		}
+		if err := feeds.SetFeedSources(code, sources, ""); err != nil {
+			t.Fatal(err)
+		}
+		newer, older := base.Add(time.Duration(i)*time.Minute), base.Add(time.Duration(i-100)*time.Minute)
+		if err := feeds.UpsertArticles([]feeds.Article{
+			{ID: code + "-new", FeedURL: url, Title: code + " newer story", Link: url + "/new", PublishedAt: &newer},
+			{ID: code + "-old", FeedURL: url, Title: code + " older story", Link: url + "/old", PublishedAt: &older},
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := feeds.UpsertArticles([]feeds.Article{
+		{ID: "wire-dated", FeedURL: shared, Title: "Wire service report on trade", Link: shared + "/1", PublishedAt: &base},
+		{ID: "wire-undated", FeedURL: shared, Title: "Wire service notice without date", Link: shared + "/2"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	codes = append(codes, "XK") // no feeds
+
+	results := GetNewsByCountries(context.Background(), codes, NewsOptions{})
+	if len(results) != len(codes) {
+		t.Fatalf("got %d results for %d countries", len(results), len(codes))
+	}
+	for i, r := range results {
+		if r.Country != codes[i] {
+			t.Errorf("result %d is %s, want %s", i, r.Country, codes[i])
+		}
+	}
+	if last := results[len(results)-1]; last.Status != CountryNoFeeds {
+		t.Errorf("XK status %q, want %q", last.Status, CountryNoFeeds)
+	}
+
+	merged := MergeCountryNews(results)
+	if want := 12*2 + 2; len(merged) != want {
+		t.Fatalf("merged %d articles, want %d (shared ones once)", len(merged), want)
+	}
+	for i := 1; i < len(merged); i++ {
+		prev, cur := merged[i-1].PublishedAt, merged[i].PublishedAt
+		if prev == nil && cur != nil || prev != nil && cur != nil && cur.After(*prev) {
+			t.Errorf("article %d (%s) is newer than article %d (%s)", i, merged[i].ID, i-1, merged[i-1].ID)
+		}
+	}
+	if first, last := merged[0].ID, merged[len(merged)-1].ID; first != "LV-new" || last != "wire-undated" {
+		t.Errorf("merged runs from %s to %s, want LV-new to wire-undated", first, last)
+	}
+	for _, r := range results {
+		if r.Articles != nil {
+			t.Errorf("%s keeps its articles after merging", r.Country)
+		}
+	}
+}
+
+func TestGetNewsByCountriesCancelled(t *testing.T) {
+	openTestDB(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for _, r := range GetNewsByCountries(ctx, []string{"DE", "FR"}, NewsOptions{}) {
+		if r.Status != CountryError {
+			t.Errorf("%s status %q after cancellation, want %q", r.Country, r.Status, CountryError)
+		}
+	}
+}
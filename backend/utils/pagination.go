@@ -0,0 +1,189 @@
+package utils
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/frogfromlake/Orbitalone/backend/feeds"
+)
+
+// Server-side bounds of paginated news queries.
+const (
+	DefaultPageLimit = 10
+	MaxPageLimit     = 50
+	// maxPagedArticlesPerFeed bounds how far back each feed can be paged
+	maxPagedArticlesPerFeed = 200
+	// pagedArticlesTTL is how long a country's paging order is reused, matching the
+	// responses' max-age
+	pagedArticlesTTL = time.Minute
+)
+
+// ErrInvalidCursor is returned for cursors that were not issued by GetNewsPage.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// PageOptions selects a page of a country's news. Articles are ordered newest first,
+// undated articles last, with ties broken by article ID.
+type PageOptions struct {
+	Limit  int        // clamped to 1..MaxPageLimit
+	Cursor string     // NextCursor of the previous page, empty for the first page
+	Since  *time.Time // only articles published at or after Since; undated ones are dropped
+}
+
+// NewsPage is a page of a country's news.
+type NewsPage struct {
+	Articles   []NewsArticle `json:"articles"`
+	NextCursor string        `json:"nextCursor,omitempty"`
+	Total      int           `json:"total"` // articles available for the query, across all pages
+}
+
+// pageCursor is the position after the last article of a page.
+type pageCursor struct {
+	published int64 // Unix nanoseconds, 0 for undated articles
+	id        string
+}
+
+// GetNewsPage returns a page of a country's ingested articles, deduplicated across feeds
+// and translated like GetNewsByCountry. Unlike GetNewsByCountry it orders purely by
+// recency, so pages stay stable while the client scrolls.
+func GetNewsPage(code string, page PageOptions, opts NewsOptions) (NewsPage, error) {
+	after, err := decodeCursor(page.Cursor)
+	if err != nil {
+		return NewsPage{}, err
+	}
+	page.Limit = min(max(page.Limit, 1), MaxPageLimit)
+
+	ordered, err := pagedArticles(code)
+	if err != nil {
+		return NewsPage{}, err
+	}
+
+	all := ordered
+	if page.Since != nil {
+		all = nil
+		for _, a := range ordered {
+			if a.PublishedAt != nil && !a.PublishedAt.Before(*page.Since) {
+				all = append(all, a)
+			}
+		}
+	}
+
+	result := NewsPage{Total: len(all)}
+	start := 0
+	if after != nil {
+		start = sort.Search(len(all), func(i int) bool { return after.before(cursorOf(all[i])) })
+	}
+	end := min(start+page.Limit, len(all))
+	result.Articles = append([]NewsArticle{}, all[start:end]...)
+	if end < len(all) {
+		result.NextCursor = encodeCursor(cursorOf(all[end-1]))
+	}
+
+	translateNews(code, result.Articles, opts)
+	return result, nil
+}
+
+// pagedArticles returns the deduplicated articles of a country's feeds in page order.
+// The list is cached for a short while, so scrolling through the pages does not load,
+// sanitize and deduplicate every stored article again.
+func pagedArticles(code string) ([]NewsArticle, error) {
+	key := "paged|" + code
+	if cached, found := feedCache.Get(key); found {
+		return cached.([]NewsArticle), nil
+	}
+
+	sources, err := feeds.GetEnabledFeedSources(code)
+	if err != nil {
+		return nil, err
+	}
+
+	perFeed := make([][]NewsArticle, len(sources)) // indexed by feed priority
+	for i, s := range sources {
+		stored, err := feeds.GetArticlesByFeed(s.URL, maxPagedArticlesPerFeed)
+		if err != nil {
+			log.Printf("❌ Failed to load articles for %s: %v", s.URL, err)
+			continue
+		}
+		for _, a := range stored {
+			if article := newsArticle(a); len(s.Keywords) == 0 || matchesKeywords(article, s.Keywords) {
+				perFeed[i] = append(perFeed[i], article)
+			}
+		}
+	}
+
+	var all []NewsArticle
+	for _, articles := range dedupeArticles(perFeed) {
+		all = append(all, articles...)
+	}
+	sort.SliceStable(all, func(i, j int) bool {
+		return cursorOf(all[i]).before(cursorOf(all[j]))
+	})
+
+	feedCache.Set(key, all, pagedArticlesTTL)
+	return all, nil
+}
+
+// cursorOf returns the sort position of an article.
+func cursorOf(a NewsArticle) pageCursor {
+	c := pageCursor{id: a.ID}
+	if a.PublishedAt != nil {
+		c.published = a.PublishedAt.UnixNano()
+	}
+	return c
+}
+
+// before reports whether c sorts before other: newer first, then by ID.
+func (c pageCursor) before(other pageCursor) bool {
+	if c.published != other.published {
+		return c.published > other.published
+	}
+	return c.id < other.id
+}
+
+// encodeCursor renders a cursor as an opaque URL-safe token.
+func encodeCursor(c pageCursor) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(c.published, 10) + ":" + c.id))
+}
+
+// decodeCursor parses a token from encodeCursor. An empty token means the first page.
+// Tokens that encodeCursor could not have produced are rejected.
+func decodeCursor(token string) (*pageCursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	published, id, ok := strings.Cut(string(raw), ":")
+	if !ok || !isArticleID(id) {
+		return nil, ErrInvalidCursor
+	}
+	nanos, err := strconv.ParseInt(published, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	if encodeCursor(pageCursor{published: nanos, id: id}) != token {
+		// Not the canonical encoding, e.g. "+5" or "007" as the time
+		return nil, ErrInvalidCursor
+	}
+	return &pageCursor{published: nanos, id: id}, nil
+}
+
+// isArticleID reports whether id has the form of an article ID: 32 lower-case hex digits.
+func isArticleID(id string) bool {
+	if len(id) != 32 {
+		return false
+	}
+	for _, r := range id {
+		if (r < '0' || r > '9') && (r < 'a' || r > 'f') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,89 @@
+package utils
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		cursor pageCursor
+	}{
+		{"dated", pageCursor{published: 1745568000000000000, id: "85f99b109b7c89fb914cb2c42a3c26e5"}},
+		{"undated", pageCursor{published: 0, id: "1c2d0000000000000000000000000000"}},
+		{"before 1970", pageCursor{published: -86400000000000, id: "ffffffffffffffffffffffffffffffff"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := encodeCursor(tt.cursor)
+			got, err := decodeCursor(token)
+			if err != nil {
+				t.Fatalf("decodeCursor(%q): %v", token, err)
+			}
+			if *got != tt.cursor {
+				t.Errorf("decodeCursor(encodeCursor(%+v)) = %+v", tt.cursor, *got)
+			}
+		})
+	}
+}
+
+func TestDecodeCursorEmpty(t *testing.T) {
+	got, err := decodeCursor("")
+	if got != nil || err != nil {
+		t.Errorf(`decodeCursor("") = %v, %v, want nil, nil`, got, err)
+	}
+}
+
+func TestDecodeCursorRejectsTampering(t *testing.T) {
+	const id = "85f99b109b7c89fb914cb2c42a3c26e5"
+	encode := func(raw string) string { return base64.RawURLEncoding.EncodeToString([]byte(raw)) }
+	valid := encodeCursor(pageCursor{published: 1745568000000000000, id: id})
+
+	tests := []struct {
+		name, token string
+	}{
+		{"not base64", "not a cursor!"},
+		{"padded base64", base64.URLEncoding.EncodeToString([]byte("1745568000000000000:" + id))},
+		{"truncated", valid[:len(valid)-3]},
+		{"no separator", encode("1745568000000000000" + id)},
+		{"empty id", encode("1745568000000000000:")},
+		{"short id", encode("1745568000000000000:85f99b")},
+		{"upper-case id", encode("1745568000000000000:85F99B109B7C89FB914CB2C42A3C26E5")},
+		{"id with injected text", encode("1745568000000000000:" + id + "' OR 1=1")},
+		{"time not a number", encode("yesterday:" + id)},
+		{"time overflows", encode("99999999999999999999:" + id)},
+		{"time with plus sign", encode("+1745568000000000000:" + id)},
+		{"time with leading zeros", encode("01745568000000000000:" + id)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeCursor(tt.token)
+			if !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("decodeCursor(%q) = %+v, %v, want ErrInvalidCursor", tt.token, got, err)
+			}
+		})
+	}
+}
+
+func TestCursorOrder(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b pageCursor
+		want bool
+	}{
+		{"newer first", pageCursor{published: 2, id: "b"}, pageCursor{published: 1, id: "a"}, true},
+		{"older after", pageCursor{published: 1, id: "a"}, pageCursor{published: 2, id: "b"}, false},
+		{"undated last", pageCursor{published: 0, id: "a"}, pageCursor{published: 1, id: "b"}, false},
+		{"ties by ID", pageCursor{published: 1, id: "a"}, pageCursor{published: 1, id: "b"}, true},
+		{"equal", pageCursor{published: 1, id: "a"}, pageCursor{published: 1, id: "a"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.a.before(tt.b); got != tt.want {
+				t.Errorf("%+v.before(%+v) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package utils
+
+import (
+	"sort"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// rankArticles merges per-feed article lists (ordered by feed priority) into at most
+// limit articles. Dated articles are sorted by recency; undated ones follow, taken
+// round-robin from the feeds in their own order. Each feed is capped to its fair share
+// of slots until the others run out.
+func rankArticles(groups [][]NewsArticle, limit int) []NewsArticle {
+	type candidate struct {
+		group, pos int
+		article    NewsArticle
+	}
+
+	var (
+		dated, undated []candidate
+		sources        int
+	)
+	for g, articles := range groups {
+		if len(articles) > 0 {
+			sources++
+		}
+		for pos, a := range articles {
+			c := candidate{group: g, pos: pos, article: a}
+			if a.PublishedAt == nil {
+				undated = append(undated, c)
+			} else {
+				dated = append(dated, c)
+			}
+		}
+	}
+	if sources == 0 || limit <= 0 {
+		return nil
+	}
+
+	sort.SliceStable(dated, func(i, j int) bool {
+		a, b := dated[i], dated[j]
+		if !a.article.PublishedAt.Equal(*b.article.PublishedAt) {
+			return a.article.PublishedAt.After(*b.article.PublishedAt)
+		}
+		if a.group != b.group {
+			return a.group < b.group
+		}
+		return a.pos < b.pos
+	})
+	sort.SliceStable(undated, func(i, j int) bool {
+		a, b := undated[i], undated[j]
+		if a.pos != b.pos {
+			return a.pos < b.pos
+		}
+		return a.group < b.group
+	})
+	candidates := append(dated, undated...)
+
+	perSourceCap := (limit + sources - 1) / sources
+	taken := make(map[int]int)
+	picked := make([]bool, len(candidates))
+
+	// First pass respects the cap, second pass fills leftover slots in ranking order
+	count := 0
+	for pass := 0; pass < 2 && count < limit; pass++ {
+		for i, c := range candidates {
+			if count >= limit {
+				break
+			}
+			if picked[i] || (pass == 0 && taken[c.group] >= perSourceCap) {
+				continue
+			}
+			picked[i] = true
+			taken[c.group]++
+			count++
+		}
+	}
+
+	// Present the selection in ranking order regardless of which pass picked it
+	result := make([]NewsArticle, 0, count)
+	for i, c := range candidates {
+		if picked[i] {
+			result = append(result, c.article)
+		}
+	}
+	return result
+}
+
+// itemTime returns the publish time of a feed item, falling back to its update time.
+func itemTime(item *gofeed.Item) *time.Time {
+	if item.PublishedParsed != nil {
+		return item.PublishedParsed
+	}
+	return item.UpdatedParsed
+}
+
+// formatPublished renders an optional publish time as RFC3339 in UTC.
+func formatPublished(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,116 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRankArticles(t *testing.T) {
+	base := time.Date(2025, 4, 25, 12, 0, 0, 0, time.UTC)
+	// article builds an article published hoursAgo hours before base; a negative value leaves it undated
+	article := func(id string, hoursAgo int) NewsArticle {
+		a := NewsArticle{ID: id}
+		if hoursAgo >= 0 {
+			published := base.Add(-time.Duration(hoursAgo) * time.Hour)
+			a.PublishedAt = &published
+		}
+		return a
+	}
+
+	tests := []struct {
+		name   string
+		groups [][]NewsArticle
+		limit  int
+		want   []string
+	}{
+		{
+			name: "newest first across feeds",
+			groups: [][]NewsArticle{
+				{article("a1", 1), article("a2", 3)},
+				{article("b1", 2), article("b2", 4)},
+			},
+			limit: 4,
+			want:  []string{"a1", "b1", "a2", "b2"},
+		},
+		{
+			name: "feed capped to its fair share",
+			groups: [][]NewsArticle{
+				{article("a1", 1), article("a2", 2), article("a3", 3), article("a4", 4)},
+				{article("b1", 5), article("b2", 6)},
+			},
+			limit: 4,
+			want:  []string{"a1", "a2", "b1", "b2"},
+		},
+		{
+			name: "leftover slots filled once other feeds run out",
+			groups: [][]NewsArticle{
+				{article("a1", 1), article("a2", 2), article("a3", 3), article("a4", 4)},
+				{article("b1", 5)},
+			},
+			limit: 4,
+			want:  []string{"a1", "a2", "a3", "b1"},
+		},
+		{
+			name: "equal times keep feed priority",
+			groups: [][]NewsArticle{
+				{article("a1", 1)},
+				{article("b1", 1)},
+			},
+			limit: 2,
+			want:  []string{"a1", "b1"},
+		},
+		{
+			name: "single undated article does not reorder dated ones",
+			groups: [][]NewsArticle{
+				{article("a1", 3), article("a2", 4)},
+				{article("b1", 1), article("b2", 2)},
+				{article("c1", -1)},
+			},
+			limit: 5,
+			want:  []string{"b1", "b2", "a1", "a2", "c1"},
+		},
+		{
+			name: "undated articles round-robin after dated ones",
+			groups: [][]NewsArticle{
+				{article("a1", -1), article("a2", -1)},
+				{article("b1", -1), article("b2", -1)},
+				{article("c1", 5)},
+			},
+			limit: 5,
+			want:  []string{"c1", "a1", "b1", "a2", "b2"},
+		},
+		{
+			name: "limit below the number of feeds",
+			groups: [][]NewsArticle{
+				{article("a1", 2)},
+				{article("b1", 1)},
+				{article("c1", 3)},
+			},
+			limit: 2,
+			want:  []string{"b1", "a1"},
+		},
+		{
+			name:   "no articles",
+			groups: [][]NewsArticle{{}, {}},
+			limit:  5,
+			want:   nil,
+		},
+		{
+			name:   "zero limit",
+			groups: [][]NewsArticle{{article("a1", 1)}},
+			limit:  0,
+			want:   nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, a := range rankArticles(tt.groups, tt.limit) {
+				got = append(got, a.ID)
+			}
+			if !equalStrings(got, tt.want) {
+				t.Errorf("rankArticles = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,11 +1,15 @@
 package utils
 
 import (
+	"context"
 	"errors"
+	"html"
 	"log"
 	"net/http"
+	"strings"
 	"sync"
 	"time"
+	"unicode/utf8"
 
 	"github.com/frogfromlake/Orbitalone/backend/feeds"
 	"github.com/frogfromlake/Orbitalone/backend/localization"
@@ -13,7 +17,8 @@ import (
 	"github.com/patrickmn/go-cache"
 )
 
-// Article cache
+// Article cache in front of the articles table. Ingested feeds are kept under their
+// URL until the poller refreshes them; per-article translations expire after 30 minutes.
 var feedCache = cache.New(30*time.Minute, 10*time.Minute)
 
 // Transport with user-agent
@@ -35,140 +40,447 @@ var parser = &gofeed.Parser{
 	},
 }
 
+// storedArticlesPerFeed bounds how many stored articles are loaded per feed.
+const storedArticlesPerFeed = 20
+
 type NewsArticle struct {
-	Title               string `json:"title"`
-	OriginalTitle       string `json:"originalTitle,omitempty"`
-	Link                string `json:"link"`
-	Description         string `json:"description,omitempty"`
-	OriginalDescription string `json:"originalDescription,omitempty"`
-	Published           string `json:"published,omitempty"`
-	Source              string `json:"source"`
-}
-
-// Temporary blacklist for feeds that failed recently
-var failedFeeds = cache.New(30*time.Minute, 10*time.Minute)
-
-// Prevents stampede on cache miss by locking per-feed URL
-var fetchLocks sync.Map // map[string]*sync.Mutex
-
-// GetNewsByCountry fetches RSS feeds for a country and optionally translates them.
-func GetNewsByCountry(code string, translate bool) ([]NewsArticle, error) {
-	feedURLs, err := feeds.GetFeeds(code)
-	if errors.Is(err, feeds.ErrNoFeeds) {
-		log.Printf("🚫 No feeds found for %s", code)
+	ID                      string            `json:"id"`
+	GUID                    string            `json:"guid,omitempty"`
+	Title                   string            `json:"title"`
+	OriginalTitle           string            `json:"originalTitle,omitempty"`
+	Link                    string            `json:"link"`
+	Description             string            `json:"description,omitempty"` // sanitized HTML
+	DescriptionText         string            `json:"descriptionText,omitempty"`
+	OriginalDescription     string            `json:"originalDescription,omitempty"` // sanitized HTML
+	OriginalDescriptionText string            `json:"originalDescriptionText,omitempty"`
+	Published               string            `json:"published,omitempty"` // RFC3339, empty if unknown
+	PublishedRaw            string            `json:"publishedRaw,omitempty"`
+	PublishedAt             *time.Time        `json:"-"`
+	Updated                 string            `json:"updated,omitempty"` // RFC3339, empty if unknown
+	Source                  string            `json:"source"`
+	Image                   string            `json:"image,omitempty"`
+	Authors                 []string          `json:"authors,omitempty"`
+	Categories              []string          `json:"categories,omitempty"`
+	Enclosures              []feeds.Enclosure `json:"enclosures,omitempty"`
+	Lead                    string            `json:"lead,omitempty"` // extracted from the article page
+	ReadingTimeMinutes      int               `json:"readingTimeMinutes,omitempty"`
+	Language                string            `json:"language,omitempty"`           // ISO 639-1 code of the original text
+	Country                 string            `json:"country,omitempty"`            // set in multi-country responses
+	TranslationStatus       string            `json:"translationStatus,omitempty"`  // pending, done or failed when translation was requested
+	TranslationSkipped      string            `json:"translationSkipped,omitempty"` // why (part of) the article was not translated
+	AlternateSources        []AlternateSource `json:"alternateSources,omitempty"`
+}
+
+// AlternateSource is a duplicate of an article from another feed that was folded into it.
+type AlternateSource struct {
+	ID     string `json:"id"`
+	Source string `json:"source"`
+	Link   string `json:"link"`
+}
+
+// Prevents stampede on cache miss by locking per country while translating
+var translateLocks sync.Map // map[string]*sync.Mutex
+
+// translatedFields is the cached translation outcome of a single article.
+type translatedFields struct {
+	Title           string
+	Description     string
+	DescriptionText string
+	Status          string // TranslationDone or TranslationFailed
+	Skipped         string // reason for NewsArticle.TranslationSkipped
+}
+
+// ErrFeedBackingOff is returned by RefreshFeed for failing feeds whose next retry is not due yet.
+var ErrFeedBackingOff = errors.New("feed is backing off after failures")
+
+// RefreshOptions are per-feed settings applied while ingesting a feed.
+type RefreshOptions struct {
+	ExtractContent bool              // fetch article pages for empty or truncated descriptions
+	Extractor      *ExtractionWorker // fetches the pages in the background; nil skips extraction
+	Language       string            // language configured for the feed source, overriding detection
+}
+
+// RefreshFeed downloads and parses a single feed and persists its items in the
+// articles table. It is called by the background poller. Feeds answering
+// 304 Not Modified are only marked as checked and not re-parsed. Failures are
+// recorded in the feed's health state and delay its next attempt exponentially.
+func RefreshFeed(ctx context.Context, url string, opts RefreshOptions) error {
+	state, err := feeds.GetFeedState(url)
+	if err != nil {
+		log.Printf("⚠️ Failed to load feed state for %s: %v", url, err)
+	}
+	if state.NextRetryAt != nil && time.Now().Before(*state.NextRetryAt) {
+		return ErrFeedBackingOff
+	}
+
+	start := time.Now()
+	result, err := fetchFeed(ctx, url, state)
+	if errors.Is(err, errNotModified) {
+		log.Printf("♻️ Feed %s not modified (%v)", url, time.Since(start))
+		return feeds.MarkFeedUnchanged(url)
+	}
+	if err != nil {
+		// Shutdown is not the feed's fault, so don't count it
+		if ctx.Err() == nil {
+			nextRetry := time.Now().Add(retryDelay(state.ConsecutiveFailures + 1))
+			if markErr := feeds.MarkFeedFailed(url, err, nextRetry); markErr != nil {
+				log.Printf("⚠️ %v", markErr)
+			}
+		}
+		return err
+	}
+	feed := result.Feed
+	log.Printf("⏱ Feed %s parsed in %v", url, time.Since(start))
+
+	articles := make([]feeds.Article, 0, len(feed.Items))
+	for _, item := range feed.Items {
+		articles = append(articles, feeds.Article{
+			ID:          feeds.ArticleID(url, item.GUID, item.Link, item.Title),
+			FeedURL:     url,
+			GUID:        item.GUID,
+			Link:        item.Link,
+			Title:       item.Title,
+			Description: item.Description,
+			Published:   firstNonEmpty(item.Published, item.Updated),
+			PublishedAt: itemTime(item),
+			Updated:     item.Updated,
+			UpdatedAt:   item.UpdatedParsed,
+			Source:      feed.Title,
+			Image:       resolveImage(item),
+			Authors:     authorNames(item),
+			Categories:  item.Categories,
+			Enclosures:  itemEnclosures(item),
+		})
+	}
+
+	feedLang := resolveLanguages(feed, feed.Items, articles, opts.Language)
+
+	if err := feeds.UpsertArticles(articles); err != nil {
+		return err
+	}
+	indexArticles(articles)
+	// Only remember validators once the items are safely stored
+	if err := feeds.MarkFeedChanged(url, result.ETag, result.LastModified, feedLang); err != nil {
+		log.Printf("⚠️ %v", err)
+	}
+
+	if opts.ExtractContent && opts.Extractor != nil {
+		opts.Extractor.Enqueue(url, articles)
+	}
+
+	// Reload lazily from the database on next read
+	invalidateFeed(url)
+	return nil
+}
+
+// invalidateFeed drops the cached articles of a feed, filtered or not.
+func invalidateFeed(url string) {
+	feedCache.Delete(url)
+	feedCache.Delete(filteredKey(url))
+}
+
+// ingestedArticles returns the stored articles for a feed URL, newest first,
+// reading through the in-memory cache.
+func ingestedArticles(url string) ([]NewsArticle, bool) {
+	if cached, found := feedCache.Get(url); found {
+		return cached.([]NewsArticle), true
+	}
+
+	stored, err := feeds.GetArticlesByFeed(url, storedArticlesPerFeed)
+	if err != nil {
+		log.Printf("❌ Failed to load articles for %s: %v", url, err)
+		return nil, false
+	}
+	if len(stored) == 0 {
+		return nil, false
+	}
+
+	articles := make([]NewsArticle, 0, len(stored))
+	for _, a := range stored {
+		articles = append(articles, newsArticle(a))
+	}
+
+	feedCache.Set(url, articles, cache.NoExpiration)
+	return articles, true
+}
+
+// newsArticle converts a stored article into its API representation in the original language.
+// Links and images that are not absolute http(s) URLs are dropped, as clients render them as-is.
+func newsArticle(a feeds.Article) NewsArticle {
+	// Show the extracted lead where the feed only ships an empty or stub description
+	description := a.Description
+	if text := htmlToText(description); a.Lead != "" && needsExtraction(text) &&
+		utf8.RuneCountInString(a.Lead) > utf8.RuneCountInString(text) {
+		description = html.EscapeString(a.Lead)
+	}
+
+	return NewsArticle{
+		ID:                 a.ID,
+		GUID:               a.GUID,
+		Title:              htmlToText(a.Title),
+		Link:               safeLink(a.Link),
+		Description:        sanitizeHTML(description),
+		DescriptionText:    htmlToText(description),
+		Published:          formatPublished(a.PublishedAt),
+		PublishedRaw:       a.Published,
+		PublishedAt:        a.PublishedAt,
+		Updated:            formatPublished(a.UpdatedAt),
+		Source:             a.Source,
+		Image:              safeLink(a.Image),
+		Authors:            a.Authors,
+		Categories:         a.Categories,
+		Enclosures:         a.Enclosures,
+		Lead:               a.Lead,
+		ReadingTimeMinutes: a.ReadingMins,
+		Language:           a.Language,
+	}
+}
+
+// NewsOptions controls how GetNewsByCountry translates the articles it returns.
+type NewsOptions struct {
+	Translator localization.Translator // nil serves the original language
+	TargetLang string
+	Worker     *TranslationWorker // when set, missing translations are queued instead of awaited
+}
+
+// GetNewsByCountry returns the ingested articles for a country, translated into
+// opts.TargetLang with opts.Translator.
+// Each article is translated from its own detected language; articles already in the
+// target language are left untouched. With a worker, articles without a cached
+// translation are returned in their original language with TranslationStatus
+// "pending" and translated in the background.
+// It never fetches feeds itself; feeds that have not been ingested yet are skipped.
+// Articles are deduplicated across feeds and ranked before translation, so only the
+// articles that are actually returned get translated.
+func GetNewsByCountry(code string, opts NewsOptions) ([]NewsArticle, error) {
+	sources, err := feeds.GetEnabledFeedSources(code)
+	if err != nil {
+		if errors.Is(err, feeds.ErrNoFeeds) {
+			log.Printf("🚫 No feeds found for %s", code)
+		}
 		return nil, err
 	}
 
-	lang, hasMapping := IsoToDeepLLang[code]
-	if !translate {
+	if opts.Translator == nil {
 		log.Printf("🌐 Translation disabled for %s – serving original language", code)
-	} else if !hasMapping {
-		log.Printf("⚠️ No DeepL mapping for %s – falling back to original", code)
-	}
-
-	var (
-		mu        sync.Mutex
-		wg        sync.WaitGroup
-		all       []NewsArticle
-		semaphore = make(chan struct{}, 4) // max 4 concurrent fetches
-		limit     = 10
-	)
-
-	for _, url := range feedURLs {
-		wg.Add(1)
-		go func(url string) {
-			defer wg.Done()
-			semaphore <- struct{}{}
-			defer func() { <-semaphore }()
-
-			if _, blacklisted := failedFeeds.Get(url); blacklisted {
-				log.Printf("🚫 Skipping blacklisted feed: %s", url)
-				return
-			}
+	}
 
-			cacheKey := url
-			if translate {
-				cacheKey += "|translated"
-			}
+	const limit = 10
 
-			var articles []NewsArticle
-			if cached, found := feedCache.Get(cacheKey); found {
-				articles = cached.([]NewsArticle)
-			} else {
-				// Prevent cache stampede
-				lockRaw, _ := fetchLocks.LoadOrStore(cacheKey, &sync.Mutex{})
-				lock := lockRaw.(*sync.Mutex)
-
-				lock.Lock()
-				defer lock.Unlock()
-
-				// Re-check cache inside the lock (double-check)
-				if cached, found := feedCache.Get(cacheKey); found {
-					articles = cached.([]NewsArticle)
-				} else {
-					start := time.Now()
-					feed, err := parser.ParseURL(url)
-					if err != nil {
-						log.Printf("⚠️ Failed to parse %s: %v", url, err)
-						failedFeeds.Set(url, true, cache.DefaultExpiration)
-						return
-					}
-					log.Printf("⏱ Feed %s parsed in %v", url, time.Since(start))
-
-					for _, item := range feed.Items {
-						if len(articles) >= 5 {
-							break
-						}
-
-						origTitle := item.Title
-						origDesc := item.Description
-						title := origTitle
-						desc := origDesc
-
-						if translate && hasMapping && lang != "EN" {
-							if t, err := localization.TranslateText(origTitle, lang); err == nil {
-								title = t
-							}
-							if origDesc != "" {
-								if tDesc, err := localization.TranslateText(origDesc, lang); err == nil {
-									desc = tDesc
-								}
-							}
-						}
-
-						articles = append(articles, NewsArticle{
-							Title:               title,
-							OriginalTitle:       origTitle,
-							Link:                item.Link,
-							Description:         desc,
-							OriginalDescription: origDesc,
-							Published:           item.Published,
-							Source:              feed.Title,
-						})
-					}
-
-					feedCache.Set(cacheKey, articles, cache.DefaultExpiration)
-				}
-			}
+	perFeed := make([][]NewsArticle, len(sources)) // indexed by feed priority
+	for i, s := range sources {
+		articles, ok := sourceArticles(s)
+		if !ok {
+			log.Printf("⏳ Feed %s not ingested yet", s.URL)
+			continue
+		}
+		perFeed[i] = articles
+	}
+
+	all := rankArticles(dedupeArticles(perFeed), limit)
+	translateNews(code, all, opts)
+
+	log.Printf("📦 Total articles collected for %s: %d", code, len(all))
+	return all, nil
+}
+
+// translateNews keeps the original fields of articles about to be returned and
+// translates them as requested by opts, in place or on the worker.
+func translateNews(code string, articles []NewsArticle, opts NewsOptions) {
+	for i := range articles {
+		articles[i] = keepOriginal(articles[i])
+	}
+
+	switch {
+	case opts.Translator == nil:
+	case opts.Worker != nil:
+		pending, _ := pendingTranslations(opts.Translator, code, articles, opts.TargetLang)
+		opts.Worker.Enqueue(opts.Translator, code, pending, opts.TargetLang)
+	default:
+		translateArticles(context.Background(), opts.Translator, code, articles, opts.TargetLang)
+	}
+}
 
-			mu.Lock()
-			defer mu.Unlock()
+// apply copies the translation outcome onto an article.
+func (t translatedFields) apply(a *NewsArticle) {
+	a.Title, a.Description, a.DescriptionText = t.Title, t.Description, t.DescriptionText
+	a.TranslationStatus, a.TranslationSkipped = t.Status, t.Skipped
+}
+
+// Translation states reported in NewsArticle.TranslationStatus.
+const (
+	TranslationPending = "pending" // queued, the original is served for now
+	TranslationDone    = "done"    // translated, or no translation needed
+	TranslationFailed  = "failed"  // the original is served, see TranslationSkipped
+)
+
+// Reasons reported in NewsArticle.TranslationSkipped.
+const (
+	skipBudgetSoft          = "budget_soft_limit" // near the character budget: title only
+	skipBudgetExhausted     = "budget_exhausted"  // past the character budget: original served
+	skipUnsupportedLanguage = "unsupported_language"
+)
+
+// translationRetryDelay is how long failed and partial translations are remembered
+// before the article is sent to the provider again.
+const translationRetryDelay = 5 * time.Minute
+
+// translateArticles translates titles and descriptions in place, keeping the originals
+// on failure. All pending fields are sent as one batch; results are cached per article
+// to avoid repeated work across requests, and failed articles are retried after a while.
+// Near the character budget only titles are translated; past it, originals are served.
+func translateArticles(ctx context.Context, translator localization.Translator, code string, articles []NewsArticle, targetLang string) {
+	// Prevent cache stampede: concurrent requests for a country and language wait for the first batch
+	lockRaw, _ := translateLocks.LoadOrStore(code+"|"+targetLang, &sync.Mutex{})
+	lock := lockRaw.(*sync.Mutex)
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	pending, level := pendingTranslations(translator, code, articles, targetLang)
+	if len(pending) == 0 {
+		return
+	}
+	if level != localization.BudgetOK {
+		log.Printf("💸 Translation budget at %s level – translating %d titles only", level, len(pending))
+	}
+	runTranslations(ctx, translator, code, pending, level, targetLang)
+}
 
-			if len(all) >= limit {
-				return
+// pendingTranslations applies cached translations and sets the status of articles that
+// need no provider request. It returns the remaining articles, marked pending, together
+// with the budget level they can be translated at.
+func pendingTranslations(translator localization.Translator, code string, articles []NewsArticle, targetLang string) ([]*NewsArticle, localization.BudgetLevel) {
+	level := localization.BudgetOK
+	if status, ok := localization.Budget(translator); ok {
+		level = status.Level
+	}
+
+	var pending []*NewsArticle
+	for i := range articles {
+		a := &articles[i]
+		if cached, found := feedCache.Get(translatedKey(a.ID, targetLang)); found {
+			cached.(translatedFields).apply(a)
+			continue
+		}
+
+		a.TranslationStatus = TranslationDone
+		lang, ok := sourceLanguage(code, a)
+		if !ok {
+			a.TranslationStatus, a.TranslationSkipped = TranslationFailed, skipUnsupportedLanguage
+			continue
+		}
+		if localization.SameLanguage(lang, targetLang) {
+			continue
+		}
+		if level == localization.BudgetHard {
+			a.TranslationStatus, a.TranslationSkipped = TranslationFailed, skipBudgetExhausted
+			continue
+		}
+
+		a.TranslationStatus = TranslationPending
+		pending = append(pending, a)
+	}
+	return pending, level
+}
+
+// runTranslations sends the titles and descriptions of pending articles to the translator
+// as one batch and caches the outcome per article.
+func runTranslations(ctx context.Context, translator localization.Translator, code string, pending []*NewsArticle, level localization.BudgetLevel, targetLang string) {
+	titlesOnly := level == localization.BudgetSoft
+
+	var reqs []localization.Request
+	for _, a := range pending {
+		lang, _ := sourceLanguage(code, a)
+		// Titles are plain text already; descriptions keep their sanitized markup,
+		// so links and formatting survive translation
+		reqs = append(reqs, localization.Request{Text: a.OriginalTitle, SourceLang: lang, TargetLang: targetLang, Format: localization.FormatText})
+		if a.OriginalDescription != "" && !titlesOnly {
+			reqs = append(reqs, localization.Request{Text: a.OriginalDescription, SourceLang: lang, TargetLang: targetLang, Format: localization.FormatHTML})
+		}
+	}
+
+	results := localization.TranslateAll(ctx, translator, reqs)
+
+	next := 0
+	for _, a := range pending {
+		t := translatedFields{
+			Title:           a.OriginalTitle,
+			Description:     a.OriginalDescription,
+			DescriptionText: a.OriginalDescriptionText,
+			Status:          TranslationDone,
+		}
+		if titlesOnly && a.OriginalDescription != "" {
+			t.Skipped = skipBudgetSoft
+		}
+
+		if res := results[next]; res.Err == nil {
+			t.Title = htmlToText(res.Text)
+		} else {
+			log.Printf("⚠️  Title translation failed for %s: %v", a.ID, res.Err)
+			t.Status = TranslationFailed
+			if errors.Is(res.Err, localization.ErrBudgetExceeded) {
+				t.Skipped = skipBudgetExhausted
 			}
-			remaining := limit - len(all)
-			if len(articles) > remaining {
-				all = append(all, articles[:remaining]...)
+		}
+		next++
+
+		if a.OriginalDescription != "" && !titlesOnly {
+			if res := results[next]; res.Err == nil {
+				// Never trust markup coming back from the provider either
+				t.Description = sanitizeHTML(res.Text)
+				t.DescriptionText = htmlToText(res.Text)
 			} else {
-				all = append(all, articles...)
+				log.Printf("⚠️  Description translation failed for %s: %v", a.ID, res.Err)
+				t.Status = TranslationFailed
 			}
-		}(url)
+			next++
+		}
+
+		// Failed and partial translations are retried later, so descriptions follow once budget is back
+		expiry := cache.DefaultExpiration
+		if t.Status != TranslationDone || t.Skipped != "" {
+			expiry = translationRetryDelay
+		}
+		feedCache.Set(translatedKey(a.ID, targetLang), t, expiry)
+		if t.Status == TranslationDone {
+			indexTranslation(a, t, targetLang)
+		}
+		t.apply(a)
 	}
-	wg.Wait()
+}
 
-	log.Printf("📦 Total articles collected for %s: %d", code, len(all))
-	return all, nil
+// ClearTranslatedArticles drops all cached article translations, so the next request
+// translates again through the translator's caches.
+func ClearTranslatedArticles() {
+	for key := range feedCache.Items() {
+		if strings.Contains(key, "|translated|") {
+			feedCache.Delete(key)
+		}
+	}
+}
+
+// sourceLanguage returns the DeepL source language of an article: its detected language,
+// or the country's main language when detection gave no supported answer (and for
+// articles ingested before detection existed).
+func sourceLanguage(code string, a *NewsArticle) (string, bool) {
+	if a.Language != "" && localization.IsSupportedSource(a.Language) {
+		return strings.ToUpper(a.Language), true
+	}
+	lang, ok := IsoToDeepLLang[code]
+	if !ok {
+		if a.Language != "" {
+			log.Printf("⚠️ No translation support for %s (%s) – keeping original", a.Language, a.ID)
+		} else {
+			log.Printf("⚠️ No DeepL mapping for %s – falling back to original", code)
+		}
+	}
+	return lang, ok
+}
+
+// translatedKey is the feedCache key of an article's translation into targetLang.
+func translatedKey(articleID, targetLang string) string {
+	return articleID + "|translated|" + targetLang
 }
 
 // TestFeedURL returns the parsed feed data from a given URL.
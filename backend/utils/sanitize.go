@@ -0,0 +1,148 @@
+package utils
+
+import (
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// allowedTags are the formatting elements kept in sanitized descriptions.
+// Any other element is unwrapped, keeping only its children.
+var allowedTags = map[atom.Atom]bool{
+	atom.P: true, atom.Br: true, atom.B: true, atom.Strong: true, atom.I: true,
+	atom.Em: true, atom.U: true, atom.S: true, atom.A: true, atom.Ul: true,
+	atom.Ol: true, atom.Li: true, atom.Blockquote: true, atom.Q: true,
+	atom.Cite: true, atom.Code: true, atom.Pre: true, atom.Small: true,
+	atom.Sub: true, atom.Sup: true,
+}
+
+// droppedTags are removed together with everything inside them.
+var droppedTags = map[atom.Atom]bool{
+	atom.Script: true, atom.Style: true, atom.Iframe: true, atom.Object: true,
+	atom.Embed: true, atom.Noscript: true, atom.Template: true, atom.Svg: true,
+	atom.Math: true, atom.Form: true, atom.Frame: true, atom.Frameset: true,
+	atom.Head: true, atom.Title: true, atom.Textarea: true, atom.Select: true,
+}
+
+// blockTags produce a line break when an HTML fragment is flattened to text.
+var blockTags = map[atom.Atom]bool{
+	atom.P: true, atom.Br: true, atom.Div: true, atom.Li: true, atom.Ul: true,
+	atom.Ol: true, atom.Blockquote: true, atom.Pre: true, atom.Tr: true,
+	atom.H1: true, atom.H2: true, atom.H3: true, atom.H4: true, atom.H5: true,
+	atom.H6: true, atom.Section: true, atom.Article: true, atom.Figure: true,
+}
+
+// sanitizeHTML reduces an untrusted HTML fragment to a small set of formatting tags.
+// Scripts, frames and embeds are removed, all attributes except safe link targets are
+// dropped, and links open in a new tab with rel="noopener noreferrer nofollow".
+func sanitizeHTML(fragment string) string {
+	nodes, err := parseFragment(fragment)
+	if err != nil {
+		return html.EscapeString(htmlToText(fragment))
+	}
+
+	var b strings.Builder
+	for _, n := range nodes {
+		renderSanitized(&b, n)
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// renderSanitized writes the allowed parts of a node tree to b.
+// Comments, doctypes and dropped elements are never rendered.
+func renderSanitized(b *strings.Builder, n *html.Node) {
+	switch {
+	case n.Type == html.TextNode:
+		b.WriteString(html.EscapeString(n.Data))
+		return
+	case n.Type != html.ElementNode, droppedTags[n.DataAtom]:
+		return
+	}
+
+	keep := allowedTags[n.DataAtom]
+	if keep {
+		b.WriteString("<" + n.Data)
+		if n.DataAtom == atom.A {
+			if href := safeLink(attr(n, "href")); href != "" {
+				b.WriteString(` href="` + html.EscapeString(href) + `" target="_blank" rel="noopener noreferrer nofollow"`)
+			}
+		}
+		b.WriteString(">")
+		if n.DataAtom == atom.Br {
+			return
+		}
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		renderSanitized(b, c)
+	}
+
+	if keep {
+		b.WriteString("</" + n.Data + ">")
+	}
+}
+
+// htmlToText flattens an HTML fragment to plain text: entities are decoded,
+// markup is removed and all whitespace is collapsed to single spaces.
+func htmlToText(fragment string) string {
+	if !strings.ContainsAny(fragment, "<&") {
+		return strings.Join(strings.Fields(fragment), " ")
+	}
+
+	nodes, err := parseFragment(fragment)
+	if err != nil {
+		return strings.Join(strings.Fields(html.UnescapeString(fragment)), " ")
+	}
+
+	var b strings.Builder
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && droppedTags[n.DataAtom] {
+			return
+		}
+		if n.Type == html.TextNode {
+			b.WriteString(n.Data)
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+		if n.Type == html.ElementNode && blockTags[n.DataAtom] {
+			b.WriteString(" ")
+		}
+	}
+	for _, n := range nodes {
+		walk(n)
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// parseFragment parses HTML as the content of a <div>.
+func parseFragment(fragment string) ([]*html.Node, error) {
+	context := &html.Node{Type: html.ElementNode, Data: "div", DataAtom: atom.Div}
+	return html.ParseFragment(strings.NewReader(fragment), context)
+}
+
+// safeLink returns the link if it is an absolute http(s) or mailto URL, otherwise "".
+func safeLink(raw string) string {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return ""
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https", "mailto":
+		return u.String()
+	}
+	return ""
+}
+
+// attr returns the value of an attribute of n, or "" if missing.
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
@@ -0,0 +1,91 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/frogfromlake/Orbitalone/backend/feeds"
+)
+
+func TestSanitizeHTML(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{"formatting kept", `<p>Hello <b>world</b></p>`, `<p>Hello <b>world</b></p>`},
+		{"script dropped with content", `<p>Hi</p><script>alert(1)</script>`, `<p>Hi</p>`},
+		{"uppercase script", `<SCRIPT>alert(1)</SCRIPT>text`, `text`},
+		{"style dropped", `<style>p{color:red}</style><p>Hi</p>`, `<p>Hi</p>`},
+		{"iframe dropped", `before<iframe src="https://evil.example"></iframe>after`, `beforeafter`},
+		{"event handler attributes dropped", `<p onclick="alert(1)" onmouseover="x()">Hi</p>`, `<p>Hi</p>`},
+		{"event handler on unknown tag", `<img src="x" onerror="alert(1)">text`, `text`},
+		{"unknown tags unwrapped", `<div><span>Hi</span></div>`, `Hi`},
+		{
+			"link gets noopener",
+			`<a href="https://example.com/a">link</a>`,
+			`<a href="https://example.com/a" target="_blank" rel="noopener noreferrer nofollow">link</a>`,
+		},
+		{
+			"link attributes replaced",
+			`<a href="https://example.com" target="_self" rel="opener" onclick="x()">link</a>`,
+			`<a href="https://example.com" target="_blank" rel="noopener noreferrer nofollow">link</a>`,
+		},
+		{"javascript link stripped", `<a href="javascript:alert(1)">link</a>`, `<a>link</a>`},
+		{"javascript link with spacing and case", `<a href="  JavaScript:alert(1)">link</a>`, `<a>link</a>`},
+		{"data link stripped", `<a href="data:text/html,<script>alert(1)</script>">link</a>`, `<a>link</a>`},
+		{"relative link stripped", `<a href="/local">link</a>`, `<a>link</a>`},
+		{
+			"mailto link kept",
+			`<a href="mailto:desk@example.com">mail</a>`,
+			`<a href="mailto:desk@example.com" target="_blank" rel="noopener noreferrer nofollow">mail</a>`,
+		},
+		{"comments dropped", `<p>Hi<!-- secret --></p>`, `<p>Hi</p>`},
+		{"text escaped", `1 &lt; 2 &amp; "quoted"`, `1 &lt; 2 &amp; &#34;quoted&#34;`},
+		{"br is void", `a<br>b`, `a<br>b`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sanitizeHTML(tt.in); got != tt.want {
+				t.Errorf("sanitizeHTML(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHTMLToText(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{"plain text collapsed", "  Hello \n  world ", "Hello world"},
+		{"entities decoded", "Fish &amp; chips", "Fish & chips"},
+		{"block tags separate words", "<p>One</p><p>Two</p>", "One Two"},
+		{"script content dropped", "Hi<script>alert(1)</script>", "Hi"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := htmlToText(tt.in); got != tt.want {
+				t.Errorf("htmlToText(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewsArticleLinks(t *testing.T) {
+	tests := []struct {
+		name, link, image   string
+		wantLink, wantImage string
+	}{
+		{"http links kept", "https://example.com/story", "http://img.example.com/a.jpg", "https://example.com/story", "http://img.example.com/a.jpg"},
+		{"javascript link dropped", "javascript:alert(1)", "", "", ""},
+		{"javascript with spacing and case", "  JavaScript:alert(1)", "", "", ""},
+		{"data image dropped", "https://example.com/story", "data:image/svg+xml,<svg onload=alert(1)>", "https://example.com/story", ""},
+		{"relative image dropped", "https://example.com/story", "/images/a.jpg", "https://example.com/story", ""},
+		{"surrounding whitespace trimmed", " https://example.com/story\n", "", "https://example.com/story", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newsArticle(feeds.Article{Link: tt.link, Image: tt.image})
+			if got.Link != tt.wantLink || got.Image != tt.wantImage {
+				t.Errorf("link %q, image %q, want %q, %q", got.Link, got.Image, tt.wantLink, tt.wantImage)
+			}
+		})
+	}
+}
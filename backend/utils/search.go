@@ -0,0 +1,203 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"html"
+	"log"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/frogfromlake/Orbitalone/backend/feeds"
+	"github.com/frogfromlake/Orbitalone/backend/localization"
+)
+
+// ErrEmptyQuery is returned by SearchNews for queries without any searchable word.
+var ErrEmptyQuery = errors.New("empty search query")
+
+// maxQueryTerms bounds the number of words of a search query.
+const maxQueryTerms = 10
+
+// SearchOptions filters a news search.
+type SearchOptions struct {
+	Query     string
+	Countries []string
+	Language  string // ISO 639-1 code of the matched text, original or translated
+	From      *time.Time
+	To        *time.Time
+	Limit     int
+	Offset    int
+}
+
+// SearchResult is a page of news search results.
+type SearchResult struct {
+	Query     string               `json:"query"`
+	Total     int                  `json:"total"`
+	Countries []feeds.CountryCount `json:"countries"` // matching articles per country
+	Results   []SearchResultItem   `json:"results"`
+}
+
+// SearchResultItem is a matching article. The highlights are HTML-escaped snippets of the
+// best-matching text with the matched words wrapped in <mark>.
+type SearchResultItem struct {
+	ID                   string `json:"id"`
+	Country              string `json:"country,omitempty"`
+	Title                string `json:"title"`
+	Link                 string `json:"link"`
+	Source               string `json:"source"`
+	Published            string `json:"published,omitempty"`
+	Language             string `json:"language,omitempty"`
+	MatchedIn            string `json:"matchedIn"` // "original" or "translated"
+	MatchLanguage        string `json:"matchLanguage,omitempty"`
+	TitleHighlight       string `json:"titleHighlight"`
+	DescriptionHighlight string `json:"descriptionHighlight,omitempty"`
+}
+
+// SearchNews searches the original and translated text of all ingested articles.
+// Every word of the query must match; the last word also matches as a prefix.
+func SearchNews(opts SearchOptions) (SearchResult, error) {
+	match := ftsQuery(opts.Query)
+	if match == "" {
+		return SearchResult{}, ErrEmptyQuery
+	}
+
+	hits, total, countries, err := feeds.SearchArticles(feeds.SearchQuery{
+		Match:     match,
+		Countries: opts.Countries,
+		Language:  opts.Language,
+		From:      opts.From,
+		To:        opts.To,
+		Limit:     opts.Limit,
+		Offset:    opts.Offset,
+	})
+	if err != nil {
+		return SearchResult{}, err
+	}
+
+	result := SearchResult{
+		Query:     opts.Query,
+		Total:     total,
+		Countries: countries,
+		Results:   make([]SearchResultItem, 0, len(hits)),
+	}
+	if result.Countries == nil {
+		result.Countries = []feeds.CountryCount{}
+	}
+	for _, h := range hits {
+		result.Results = append(result.Results, SearchResultItem{
+			ID:                   h.ArticleID,
+			Country:              h.Country,
+			Title:                htmlToText(h.Title),
+			Link:                 h.Link,
+			Source:               h.Source,
+			Published:            formatPublished(h.PublishedAt),
+			Language:             h.Language,
+			MatchedIn:            h.MatchKind,
+			MatchLanguage:        h.MatchLanguage,
+			TitleHighlight:       highlight(h.TitleSnippet),
+			DescriptionHighlight: highlight(h.DescriptionSnippet),
+		})
+	}
+	return result, nil
+}
+
+// ftsQuery turns user input into a safe FTS5 expression: every word is quoted, so
+// operators and punctuation in the input have no special meaning.
+func ftsQuery(query string) string {
+	words := strings.FieldsFunc(query, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	if len(words) > maxQueryTerms {
+		words = words[:maxQueryTerms]
+	}
+	if len(words) == 0 {
+		return ""
+	}
+
+	terms := make([]string, len(words))
+	for i, w := range words {
+		terms[i] = `"` + w + `"`
+	}
+	terms[len(terms)-1] += "*"
+	return strings.Join(terms, " ")
+}
+
+// highlight escapes a snippet and turns its match markers into <mark> tags.
+func highlight(snippet string) string {
+	escaped := html.EscapeString(snippet)
+	escaped = strings.ReplaceAll(escaped, feeds.SnippetMatchStart, "<mark>")
+	return strings.ReplaceAll(escaped, feeds.SnippetMatchEnd, "</mark>")
+}
+
+// indexArticles adds the original text of freshly ingested articles to the search index.
+func indexArticles(articles []feeds.Article) {
+	docs := make([]feeds.SearchDocument, 0, len(articles))
+	for _, a := range articles {
+		docs = append(docs, originalDocument(a))
+	}
+	if err := feeds.IndexDocuments(docs); err != nil {
+		log.Printf("⚠️ Failed to index articles: %v", err)
+	}
+}
+
+// indexTranslation adds an article's translation to the search index.
+// Free-standing text items are not articles and are never indexed.
+func indexTranslation(a *NewsArticle, t translatedFields, targetLang string) {
+	if strings.HasPrefix(a.ID, textItemPrefix) {
+		return
+	}
+	doc := feeds.SearchDocument{
+		ArticleID:   a.ID,
+		Kind:        feeds.SearchTranslated,
+		Language:    localization.NormalizeLanguage(targetLang),
+		Title:       t.Title,
+		Description: t.DescriptionText,
+	}
+	if err := feeds.IndexDocuments([]feeds.SearchDocument{doc}); err != nil {
+		log.Printf("⚠️ Failed to index translation of %s: %v", a.ID, err)
+	}
+}
+
+// originalDocument is the searchable plain text of a stored article.
+func originalDocument(a feeds.Article) feeds.SearchDocument {
+	n := newsArticle(a)
+	return feeds.SearchDocument{
+		ArticleID:   a.ID,
+		Kind:        feeds.SearchOriginal,
+		Language:    a.Language,
+		Title:       n.Title,
+		Description: n.DescriptionText,
+	}
+}
+
+// BackfillSearchIndex indexes stored articles that were ingested before search existed.
+// It runs until every article is indexed, an error occurs or ctx is cancelled.
+func BackfillSearchIndex(ctx context.Context) {
+	const batch = 500
+
+	indexed := 0
+	for ctx.Err() == nil {
+		articles, err := feeds.UnindexedArticles(batch)
+		if err != nil {
+			log.Printf("⚠️ Search backfill stopped: %v", err)
+			return
+		}
+		if len(articles) == 0 {
+			break
+		}
+
+		docs := make([]feeds.SearchDocument, 0, len(articles))
+		for _, a := range articles {
+			docs = append(docs, originalDocument(a))
+		}
+		if err := feeds.IndexDocuments(docs); err != nil {
+			log.Printf("⚠️ Search backfill stopped: %v", err)
+			return
+		}
+		indexed += len(docs)
+	}
+	if indexed > 0 {
+		log.Printf("🔎 Indexed %d stored articles for search", indexed)
+	}
+}
@@ -0,0 +1,127 @@
+package utils
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/frogfromlake/Orbitalone/backend/feeds"
+	"github.com/frogfromlake/Orbitalone/backend/localization"
+)
+
+// textItemPrefix marks the IDs of text items, which are not stored articles.
+const textItemPrefix = "text-"
+
+// TextItem is a title and description to translate that is not (or not yet) a stored article.
+// The source language is taken from SourceLang, then from the country's main language,
+// and is detected from the text when neither is given.
+type TextItem struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"` // HTML or plain text
+	Country     string `json:"country,omitempty"`
+	SourceLang  string `json:"sourceLang,omitempty"`
+}
+
+// TranslateArticlesByID translates stored articles on demand, sharing the per-article,
+// text and persistent caches and the budget guard with /api/news.
+// It returns the translations in the order of ids and the IDs that are not stored.
+func TranslateArticlesByID(ctx context.Context, translator localization.Translator, ids []string, targetLang string) ([]ArticleTranslation, []string, error) {
+	var (
+		byCountry = make(map[string][]NewsArticle)
+		countries []string
+		missing   []string
+	)
+	for _, id := range ids {
+		stored, err := feeds.GetArticle(id)
+		if errors.Is(err, sql.ErrNoRows) {
+			missing = append(missing, id)
+			continue
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("loading article %s: %w", id, err)
+		}
+
+		// The country only matters for articles without a detected language
+		code, err := feeds.GetFeedCountry(stored.FeedURL)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return nil, nil, fmt.Errorf("loading country of %s: %w", stored.FeedURL, err)
+		}
+		if _, seen := byCountry[code]; !seen {
+			countries = append(countries, code)
+		}
+		byCountry[code] = append(byCountry[code], keepOriginal(newsArticle(*stored)))
+	}
+
+	translated := make(map[string]ArticleTranslation, len(ids))
+	for _, code := range countries {
+		articles := byCountry[code]
+		translateArticles(ctx, translator, code, articles, targetLang)
+		for _, a := range articles {
+			translated[a.ID] = articleTranslation(a)
+		}
+	}
+
+	out := make([]ArticleTranslation, 0, len(translated))
+	for _, id := range ids {
+		if t, ok := translated[id]; ok {
+			out = append(out, t)
+		}
+	}
+	return out, missing, nil
+}
+
+// TranslateTextItems translates free-standing titles and descriptions, in order.
+// Items are identified by a hash of their content, so repeated requests hit the caches.
+func TranslateTextItems(ctx context.Context, translator localization.Translator, items []TextItem, targetLang string) []ArticleTranslation {
+	out := make([]ArticleTranslation, len(items))
+	for i, item := range items {
+		code := strings.ToUpper(item.Country)
+		a := NewsArticle{
+			ID:              textItemID(item),
+			Title:           htmlToText(item.Title),
+			Description:     sanitizeHTML(item.Description),
+			DescriptionText: htmlToText(item.Description),
+			Language:        localization.NormalizeLanguage(item.SourceLang),
+		}
+		if a.Language == "" && IsoToDeepLLang[code] == "" {
+			if lang, ok := localization.DetectLanguage(a.Title + ". " + a.DescriptionText); ok {
+				a.Language = lang
+			}
+		}
+
+		articles := []NewsArticle{keepOriginal(a)}
+		translateArticles(ctx, translator, code, articles, targetLang)
+		out[i] = articleTranslation(articles[0])
+	}
+	return out
+}
+
+// keepOriginal records an article's original fields before they are translated in place.
+func keepOriginal(a NewsArticle) NewsArticle {
+	a.OriginalTitle = a.Title
+	a.OriginalDescription = a.Description
+	a.OriginalDescriptionText = a.DescriptionText
+	return a
+}
+
+// articleTranslation reports the translation outcome of an article.
+func articleTranslation(a NewsArticle) ArticleTranslation {
+	return ArticleTranslation{
+		ID:                 a.ID,
+		Status:             a.TranslationStatus,
+		Title:              a.Title,
+		Description:        a.Description,
+		DescriptionText:    a.DescriptionText,
+		TranslationSkipped: a.TranslationSkipped,
+	}
+}
+
+// textItemID derives a stable ID from a text item's content and source language.
+func textItemID(item TextItem) string {
+	sum := sha256.Sum256([]byte(strings.ToUpper(item.Country) + "\x00" + item.SourceLang + "\x00" + item.Title + "\x00" + item.Description))
+	return textItemPrefix + hex.EncodeToString(sum[:16])
+}
@@ -0,0 +1,166 @@
+package utils
+
+import (
+	"context"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+
+	"github.com/frogfromlake/Orbitalone/backend/localization"
+)
+
+// translationQueueSize bounds how many batches may wait for a translation worker.
+// Requests arriving while the queue is full are served untranslated and queue again later.
+const translationQueueSize = 64
+
+// translationJob is a batch of articles of one country to translate into a language.
+type translationJob struct {
+	translator localization.Translator
+	code       string
+	targetLang string
+	articles   []NewsArticle
+}
+
+// ArticleTranslation is the translation state of a single article, as reported
+// by the translation status endpoint.
+type ArticleTranslation struct {
+	ID                 string `json:"id"`
+	Status             string `json:"translationStatus"`
+	Title              string `json:"title,omitempty"`
+	Description        string `json:"description,omitempty"` // sanitized HTML
+	DescriptionText    string `json:"descriptionText,omitempty"`
+	TranslationSkipped string `json:"translationSkipped,omitempty"`
+}
+
+// TranslationWorker translates articles in the background, so news requests can
+// return the original articles right away.
+type TranslationWorker struct {
+	workers int
+	jobs    chan translationJob
+	queued  sync.Map // translatedKey -> struct{}, articles waiting for or in translation
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+// NewTranslationWorker creates a worker pool with the given number of goroutines.
+// Call Start to run it.
+func NewTranslationWorker(workers int) *TranslationWorker {
+	if workers < 1 {
+		workers = 1
+	}
+	return &TranslationWorker{
+		workers: workers,
+		jobs:    make(chan translationJob, translationQueueSize),
+	}
+}
+
+// TranslationWorkersFromEnv reads the number of translation workers from
+// TRANSLATION_WORKERS, defaulting to 2.
+func TranslationWorkersFromEnv() int {
+	if raw := os.Getenv("TRANSLATION_WORKERS"); raw != "" {
+		if n, err := strconv.Atoi(raw); err == nil && n > 0 {
+			return n
+		}
+		log.Printf("⚠️  Invalid TRANSLATION_WORKERS %q, using 2", raw)
+	}
+	return 2
+}
+
+// Start launches the workers in the background.
+func (w *TranslationWorker) Start(ctx context.Context) {
+	ctx, w.cancel = context.WithCancel(ctx)
+	w.done = make(chan struct{})
+
+	var wg sync.WaitGroup
+	for i := 0; i < w.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case job := <-w.jobs:
+					w.run(ctx, job)
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(w.done)
+	}()
+	log.Printf("🌐 Translation worker started (%d workers)", w.workers)
+}
+
+// Stop cancels in-flight translations and blocks until all workers have exited.
+func (w *TranslationWorker) Stop() {
+	if w.cancel == nil {
+		return
+	}
+	w.cancel()
+	<-w.done
+	log.Println("🛑 Translation worker stopped")
+}
+
+// Enqueue queues the given articles for translation, skipping articles that are already
+// queued. It never blocks; when the queue is full the articles are dropped and queued
+// again by a later request.
+func (w *TranslationWorker) Enqueue(translator localization.Translator, code string, articles []*NewsArticle, targetLang string) {
+	job := translationJob{translator: translator, code: code, targetLang: targetLang}
+	for _, a := range articles {
+		if _, loaded := w.queued.LoadOrStore(translatedKey(a.ID, targetLang), struct{}{}); !loaded {
+			job.articles = append(job.articles, *a)
+		}
+	}
+	if len(job.articles) == 0 {
+		return
+	}
+
+	select {
+	case w.jobs <- job:
+		log.Printf("📥 Queued %d articles of %s for translation into %s", len(job.articles), code, targetLang)
+	default:
+		log.Printf("⚠️  Translation queue full – dropping %d articles of %s", len(job.articles), code)
+		w.release(job)
+	}
+}
+
+// Status reports the translation state of the given articles. Articles that are neither
+// queued nor have a remembered outcome are omitted; fetching the news again queues them.
+func (w *TranslationWorker) Status(ids []string, targetLang string) []ArticleTranslation {
+	out := make([]ArticleTranslation, 0, len(ids))
+	for _, id := range ids {
+		key := translatedKey(id, targetLang)
+		if cached, found := feedCache.Get(key); found {
+			t := cached.(translatedFields)
+			out = append(out, ArticleTranslation{
+				ID:                 id,
+				Status:             t.Status,
+				Title:              t.Title,
+				Description:        t.Description,
+				DescriptionText:    t.DescriptionText,
+				TranslationSkipped: t.Skipped,
+			})
+			continue
+		}
+		if _, queued := w.queued.Load(key); queued {
+			out = append(out, ArticleTranslation{ID: id, Status: TranslationPending})
+		}
+	}
+	return out
+}
+
+// run translates a queued batch and releases its articles.
+func (w *TranslationWorker) run(ctx context.Context, job translationJob) {
+	defer w.release(job)
+	translateArticles(ctx, job.translator, job.code, job.articles, job.targetLang)
+}
+
+// release forgets that the articles of a job are queued.
+func (w *TranslationWorker) release(job translationJob) {
+	for _, a := range job.articles {
+		w.queued.Delete(translatedKey(a.ID, job.targetLang))
+	}
+}
@@ -0,0 +1,152 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/frogfromlake/Orbitalone/backend/localization"
+)
+
+// gatedTranslator upper-cases texts once its gate opens, or fails when the context ends first.
+type gatedTranslator struct {
+	gate    chan struct{}
+	batches atomic.Int32
+}
+
+func (g *gatedTranslator) Name() string { return "gated" }
+
+func (g *gatedTranslator) TranslateBatch(ctx context.Context, texts []string, sourceLang, targetLang string, format localization.Format) ([]string, error) {
+	g.batches.Add(1)
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-g.gate:
+	}
+	out := make([]string, len(texts))
+	for i, text := range texts {
+		out[i] = strings.ToUpper(text)
+	}
+	return out, nil
+}
+
+func workerArticles(prefix string, n int) []*NewsArticle {
+	var articles []*NewsArticle
+	for i := range n {
+		title := fmt.Sprintf("Nachricht %d", i)
+		articles = append(articles, &NewsArticle{ID: fmt.Sprintf("%s-%d", prefix, i), Title: title, OriginalTitle: title, Language: "de"})
+	}
+	return articles
+}
+
+func statusIDs(statuses []ArticleTranslation, status string) []string {
+	var ids []string
+	for _, s := range statuses {
+		if s.Status == status {
+			ids = append(ids, s.ID)
+		}
+	}
+	return ids
+}
+
+func TestTranslationWorker(t *testing.T) {
+	openTestDB(t)
+	translator := &gatedTranslator{gate: make(chan struct{})}
+	w := NewTranslationWorker(1)
+	w.Start(context.Background())
+	defer w.Stop()
+
+	articles := workerArticles(t.Name(), 2)
+	ids := []string{articles[0].ID, articles[1].ID, "never-queued"}
+	w.Enqueue(translator, "DE", articles, "EN")
+	w.Enqueue(translator, "DE", articles, "EN") // already queued
+
+	if got := statusIDs(w.Status(ids, "EN"), TranslationPending); len(got) != 2 {
+		t.Fatalf("pending = %v, want both articles", got)
+	}
+
+	close(translator.gate)
+	deadline := time.Now().Add(5 * time.Second)
+	var statuses []ArticleTranslation
+	for {
+		statuses = w.Status(ids, "EN")
+		if len(statusIDs(statuses, TranslationDone)) == 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("translations not done: %+v", statuses)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if len(statuses) != 2 {
+		t.Errorf("reported %d articles, want the two queued ones", len(statuses))
+	}
+	if statuses[0].Title != "NACHRICHT 0" {
+		t.Errorf("title = %q", statuses[0].Title)
+	}
+	if n := translator.batches.Load(); n != 1 {
+		t.Errorf("translated %d batches, want 1", n)
+	}
+}
+
+func TestTranslationWorkerDropsWhenQueueFull(t *testing.T) {
+	w := NewTranslationWorker(1) // not started: nothing drains the queue
+	translator := &gatedTranslator{gate: make(chan struct{})}
+
+	var ids []string
+	for i := range translationQueueSize + 1 {
+		articles := workerArticles(fmt.Sprintf("%s-%d", t.Name(), i), 1)
+		w.Enqueue(translator, "DE", articles, "EN")
+		ids = append(ids, articles[0].ID)
+	}
+
+	statuses := w.Status(ids, "EN")
+	if len(statuses) != translationQueueSize {
+		t.Fatalf("%d articles queued, want %d", len(statuses), translationQueueSize)
+	}
+	if last := statuses[len(statuses)-1].ID; last == ids[len(ids)-1] {
+		t.Error("the batch past the queue size was queued")
+	}
+}
+
+func TestTranslationWorkerStopCancelsTranslations(t *testing.T) {
+	openTestDB(t)
+	translator := &gatedTranslator{gate: make(chan struct{})} // never opens
+	w := NewTranslationWorker(2)
+	w.Start(context.Background())
+
+	articles := workerArticles(t.Name(), 1)
+	w.Enqueue(translator, "DE", articles, "EN")
+	for translator.batches.Load() == 0 {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		w.Stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Stop did not return while a translation was in flight")
+	}
+
+	got := w.Status([]string{articles[0].ID}, "EN")
+	if len(got) != 1 || got[0].Status != TranslationFailed {
+		t.Errorf("status after Stop = %+v, want failed", got)
+	}
+}
+
+func TestTranslationWorkersFromEnv(t *testing.T) {
+	for value, want := range map[string]int{"": 2, "6": 6, "0": 2, "many": 2} {
+		t.Setenv("TRANSLATION_WORKERS", value)
+		if got := TranslationWorkersFromEnv(); got != want {
+			t.Errorf("TRANSLATION_WORKERS=%q: %d workers, want %d", value, got, want)
+		}
+	}
+}